@@ -0,0 +1,126 @@
+package chars
+
+/*
+bom.go
+
+Classify the byte order mark (if any) found at the start of a file
+https://en.wikipedia.org/wiki/Byte_order_mark#Byte-order_marks_by_encoding
+*/
+
+import (
+	"bytes"
+	"slices"
+	"sort"
+)
+
+// byte order marks reported in SpecialChars.Bom
+const (
+	BomNone      string = ""
+	BomUtf8      string = "UTF-8"
+	BomUtf16le   string = "UTF-16LE"
+	BomUtf16be   string = "UTF-16BE"
+	BomUtf32le   string = "UTF-32LE"
+	BomUtf32be   string = "UTF-32BE"
+	BomUtf7      string = "UTF-7"
+	BomUtf1      string = "UTF-1"
+	BomUtfEbcdic string = "UTF-EBCDIC"
+	BomScsu      string = "SCSU"
+	BomBocu1     string = "BOCU-1"
+	BomGb18030   string = "GB-18030"
+)
+
+// utf8Bom - the only byte order mark which can be added with --add-bom
+var utf8Bom = []byte{0xef, 0xbb, 0xbf}
+
+// bomMaxLength - the longest byte order mark in knownBoms
+const bomMaxLength int = 4
+
+// bomSignature - class is the name used with -f; family groups both byte orders of UTF-16 and UTF-32
+type bomSignature struct {
+	name   string
+	class  string
+	family string
+	mark   []byte
+}
+
+// knownBoms - longer marks must come first so that a UTF-32LE BOM is not mistaken for UTF-16LE
+var knownBoms = []bomSignature{
+	{BomUtf32le, "bom32le", "bom32", []byte{0xff, 0xfe, 0x00, 0x00}},
+	{BomUtf32be, "bom32be", "bom32", []byte{0x00, 0x00, 0xfe, 0xff}},
+	{BomUtfEbcdic, "bomebcdic", "", []byte{0xdd, 0x73, 0x66, 0x73}},
+	{BomGb18030, "bomgb18030", "", []byte{0x84, 0x31, 0x95, 0x33}},
+	{BomUtf7, "bom7", "", []byte{0x2b, 0x2f, 0x76, 0x38}},
+	{BomUtf7, "bom7", "", []byte{0x2b, 0x2f, 0x76, 0x39}},
+	{BomUtf7, "bom7", "", []byte{0x2b, 0x2f, 0x76, 0x2b}},
+	{BomUtf7, "bom7", "", []byte{0x2b, 0x2f, 0x76, 0x2f}},
+	{BomUtf8, "bom8", "", utf8Bom},
+	{BomUtf1, "bom1", "", []byte{0xf7, 0x64, 0x4c}},
+	{BomScsu, "bomscsu", "", []byte{0x0e, 0xfe, 0xff}},
+	{BomBocu1, "bombocu1", "", []byte{0xfb, 0xee, 0x28}},
+	{BomUtf16le, "bom16le", "bom16", []byte{0xff, 0xfe}},
+	{BomUtf16be, "bom16be", "bom16", []byte{0xfe, 0xff}},
+}
+
+// detectBom - return the name of the byte order mark at the start of peeked, or BomNone
+func detectBom(peeked []byte) string {
+	for _, sig := range knownBoms {
+		if bytes.HasPrefix(peeked, sig.mark) {
+			return sig.name
+		}
+	}
+	return BomNone
+}
+
+// bomLength - return the number of bytes used by the named byte order mark
+func bomLength(name string) int {
+	for _, sig := range knownBoms {
+		if sig.name == name {
+			return len(sig.mark)
+		}
+	}
+	return 0
+}
+
+// isBomClass - return true if class is a -f name for a byte order mark
+func isBomClass(class string) bool {
+	if class == "bom" {
+		return true
+	}
+	for _, sig := range knownBoms {
+		if sig.class == class || sig.family == class {
+			return true
+		}
+	}
+	return false
+}
+
+// bomClassNames - every -f name for a byte order mark other than bom, in alphabetical order
+func bomClassNames() []string {
+	var names []string
+	for _, sig := range knownBoms {
+		for _, class := range []string{sig.class, sig.family} {
+			if class != "" && !slices.Contains(names, class) {
+				names = append(names, class)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// bomMatchesClass - return true if the named byte order mark belongs to the given -f class;
+// bom16 and bom32 match either byte order and bom matches any byte order mark
+func bomMatchesClass(name, class string) bool {
+	if name == BomNone {
+		return false
+	}
+	if class == "bom" {
+		return true
+	}
+	for _, sig := range knownBoms {
+		if sig.name == name && (sig.class == class || sig.family == class) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package chars
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestDetectBom - a UTF-32LE BOM starts with the UTF-16LE one, so the longer mark must be checked first
+func TestDetectBom(t *testing.T) {
+	tests := []struct {
+		peeked []byte
+		want   string
+		length int
+	}{
+		{[]byte{0xff, 0xfe, 0x00, 0x00, 'a', 0x00, 0x00, 0x00}, BomUtf32le, 4},
+		{[]byte{0xff, 0xfe, 'a', 0x00}, BomUtf16le, 2},
+		{[]byte{0xff, 0xfe, 0x00, 0x01}, BomUtf16le, 2}, // U+0100 after a UTF-16LE BOM
+		{[]byte{0xff, 0xfe}, BomUtf16le, 2},
+		{[]byte{0x00, 0x00, 0xfe, 0xff}, BomUtf32be, 4},
+		{[]byte{0xfe, 0xff, 0x00, 'a'}, BomUtf16be, 2},
+		{[]byte{0xef, 0xbb, 0xbf, 'a'}, BomUtf8, 3},
+		{[]byte{0x2b, 0x2f, 0x76, 0x38}, BomUtf7, 4},
+		{[]byte{0x2b, 0x2f, 0x76}, BomNone, 0},
+		{[]byte{0xef, 0xbb}, BomNone, 0},
+		{[]byte("abc"), BomNone, 0},
+		{nil, BomNone, 0},
+	}
+	for _, tt := range tests {
+		got := detectBom(tt.peeked)
+		if got != tt.want || bomLength(got) != tt.length {
+			t.Errorf("% x: %q of %d bytes, want %q of %d bytes", tt.peeked, got, bomLength(got), tt.want, tt.length)
+		}
+	}
+}
+
+// TestSortByBomClass - every byte order mark class is a sort key which puts the files with a matching BOM last
+func TestSortByBomClass(t *testing.T) {
+	for _, class := range bomClassNames() {
+		if !slices.Contains(GetValidSortColumns(), class) {
+			t.Errorf("%s is not a valid sort column", class)
+		}
+	}
+
+	entries := []SpecialChars{{Filename: "u32", Bom: BomUtf32le}, {Filename: "u16", Bom: BomUtf16be}, {Filename: "none"}}
+	tests := []struct {
+		class string
+		want  []string
+	}{
+		{"bom32", []string{"u16", "none", "u32"}},
+		{"bom32le", []string{"u16", "none", "u32"}},
+		{"bom16be", []string{"u32", "none", "u16"}},
+		{"bom16le", []string{"u32", "u16", "none"}},
+	}
+	for _, tt := range tests {
+		sorted := slices.Clone(entries)
+		SortByColumn(sorted, tt.class)
+		var got []string
+		for _, s := range sorted {
+			got = append(got, s.Filename)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("-s %s sorted %v, want %v", tt.class, got, tt.want)
+		}
+	}
+}
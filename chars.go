@@ -11,7 +11,6 @@ package chars
 
 import (
 	"bufio"
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -26,26 +25,128 @@ import (
 
 	"github.com/jftuga/ellipsis"
 	"github.com/olekukonko/tablewriter"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
 )
 
 const PgmName string = "chars"
 const PgmDesc string = "Determine the end-of-line format, tabs, bom, nul and non-ascii"
 const PgmUrl string = "https://github.com/jftuga/chars"
-const PgmVersion string = "2.7.0"
+const PgmVersion string = "3.0.0"
 const BlockSize int = 4096
 
+// end-of-line styles reported in SpecialChars.Eol
+const (
+	EolNone  string = "none"
+	EolLf    string = "lf"
+	EolCrlf  string = "crlf"
+	EolCr    string = "cr"
+	EolMixed string = "mixed"
+)
+
 type SpecialChars struct {
-	Filename               string `json:"filename"`
-	Crlf                   uint64 `json:"crlf"`
-	Lf                     uint64 `json:"lf"`
-	Tab                    uint64 `json:"tab"`
-	Bom8                   uint64 `json:"bom8"`
-	Bom16                  uint64 `json:"bom16"`
-	Nul                    uint64 `json:"nul"`
-	NonAscii               uint64 `json:"nonAscii"`
-	MaxConsecutiveNonAscii uint64 `json:"maxConsecutiveNonAscii"`
-	BytesRead              uint64 `json:"bytesRead"`
-	Failure                bool   `json:"failure"`
+	Filename               string            `json:"filename"`
+	Crlf                   uint64            `json:"crlf"`
+	Lf                     uint64            `json:"lf"`
+	Cr                     uint64            `json:"cr"`
+	Eol                    string            `json:"eol"`
+	Tab                    uint64            `json:"tab"`
+	TrailingWhitespace     uint64            `json:"trailingWhitespace"`
+	NoFinalNewline         bool              `json:"noFinalNewline"`
+	TrailingBlankLines     uint64            `json:"trailingBlankLines"`
+	Indent                 string            `json:"indent"`
+	IndentWidth            int               `json:"indentWidth"`
+	TabIndentedLines       uint64            `json:"tabIndentedLines"`
+	SpaceIndentedLines     uint64            `json:"spaceIndentedLines"`
+	MixedIndentedLines     uint64            `json:"mixedIndentedLines"`
+	Lines                  uint64            `json:"lines"`
+	MaxLineBytes           uint64            `json:"maxLineBytes"`
+	MaxLineColumns         uint64            `json:"maxLineColumns"`
+	AverageLineColumns     float64           `json:"averageLineColumns"`
+	LongLines              uint64            `json:"longLines"`
+	Bom8                   uint64            `json:"bom8"`
+	Bom16                  uint64            `json:"bom16"`
+	Bom                    string            `json:"bom"`
+	Nul                    uint64            `json:"nul"`
+	NonAscii               uint64            `json:"nonAscii"`
+	MaxConsecutiveNonAscii uint64            `json:"maxConsecutiveNonAscii"`
+	Utf8Multibyte          uint64            `json:"utf8Multibyte"`
+	Utf8Invalid            uint64            `json:"utf8Invalid"`
+	Utf8Overlong           uint64            `json:"utf8Overlong"`
+	Utf8Surrogate          uint64            `json:"utf8Surrogate"`
+	FirstInvalidUtf8       int64             `json:"firstInvalidUtf8"`
+	Bidi                   uint64            `json:"bidi"`
+	Invisible              uint64            `json:"invisible"`
+	Confusable             uint64            `json:"confusable"`
+	Confusables            []ConfusableChar  `json:"confusables,omitempty"`
+	Histogram              []HistogramEntry  `json:"histogram,omitempty"`
+	Scripts                map[string]uint64 `json:"scripts,omitempty"`
+	Blocks                 map[string]uint64 `json:"blocks,omitempty"`
+	MixedScript            uint64            `json:"mixedScript"`
+	Nfc                    bool              `json:"nfc"`
+	NfcCodePoints          uint64            `json:"nfcCodePoints"`
+	NfcLines               uint64            `json:"nfcLines"`
+	NfkcCodePoints         uint64            `json:"nfkcCodePoints"`
+	NfkcLines              uint64            `json:"nfkcLines"`
+	FormFeed               uint64            `json:"formFeed"`
+	VerticalTab            uint64            `json:"verticalTab"`
+	Backspace              uint64            `json:"backspace"`
+	Escape                 uint64            `json:"escape"`
+	AnsiSequences          uint64            `json:"ansiSequences"`
+	Del                    uint64            `json:"del"`
+	OtherC0                uint64            `json:"otherC0"`
+	C1                     uint64            `json:"c1"`
+	BytesRead              uint64            `json:"bytesRead"`
+	DecodedFrom            string            `json:"decodedFrom,omitempty"`
+	Encoding               string            `json:"encoding,omitempty"`
+	EncodingConfidence     float64           `json:"encodingConfidence,omitempty"`
+	Locations              []Location        `json:"locations,omitempty"`
+	LocationsOmitted       uint64            `json:"locationsOmitted,omitempty"`
+	Context                []ContextLine     `json:"context,omitempty"`
+	Fix                    *FixResult        `json:"fix,omitempty"`
+	Suggestion             string            `json:"suggestion,omitempty"`
+	Failure                bool              `json:"failure"`
+	hunks                  []diffHunk        // with --suggest, the changes which give the normal form
+}
+
+// Options - settings that change how each file is examined
+type Options struct {
+	ExamineBinary   bool     // examine files that appear to contain binary data
+	RawBytes        bool     // count bytes as-is instead of decoding UTF-16 and UTF-32 input
+	DetectEncoding  bool     // guess the character encoding of each file
+	Locations       bool     // record the line and column of each matching character
+	LocationClasses []string // -f style class names to locate; defaultLocationClasses when empty
+	MaxLocations    int      // stop recording locations for a file after this many; 0 is unlimited
+	Context         bool     // keep the lines surrounding each location
+	ContextLines    int      // with Context, lines to keep before and after each location
+	Recursive       bool     // examine all files below each directory
+	Include         []string // only examine files matching one of these globs
+	Exclude         []string // skip files and directories matching one of these globs
+	MaxDepth        int      // with Recursive, how many directory levels to descend; 0 is unlimited
+	Hidden          bool     // with Recursive, also examine files and directories starting with a dot
+	FollowSymlinks  bool     // with Recursive, follow symbolic links to files and directories
+	NoIgnore        bool     // with Recursive, do not honor .gitignore, .charsignore and other ignore files
+	Workers         int      // number of files examined concurrently; runtime.NumCPU() when 0
+	FixEol          string   // rewrite every line ending as EolLf or EolCrlf
+	DryRun          bool     // report what the fix modes would change without writing
+	PreserveMtime   bool     // keep the modification time of files rewritten by the fix modes
+	StripBom        bool     // remove the byte order mark from files which have one
+	AddBom          bool     // add a UTF-8 byte order mark to files which do not have one
+	ToUtf8          bool     // transcode UTF-16, UTF-32 and FromEncoding files to UTF-8
+	FromEncoding    string   // with ToUtf8, the encoding of files which are not valid UTF-8
+	Lossy           bool     // with ToUtf8, write files even when some characters can not be converted
+	ExpandTabs      int      // replace tabs with spaces using tab stops this many columns apart
+	UnexpandTabs    int      // replace the spaces used for indentation with tabs this many columns wide
+	StripTrailingWs bool     // remove spaces and tabs at the end of each line
+	Suggest         bool     // compare each file with its normal form without writing anything
+	MaxLine         int      // lines wider than this many display columns are counted in LongLines; 0 is unlimited
+	Confusables     bool     // list each non-ASCII character which looks like an ASCII character
+	Histogram       bool     // count each distinct non-ASCII character
+	HistogramSort   string   // with Histogram, HistogramSortCount or HistogramSortCodePoint
+	Scripts         bool     // count the characters of each script and the non-ASCII characters of each block
+	Normalize       string   // rewrite the text in NormNfc or NormNfkc
+	Nfkc            bool     // count the characters and lines which are not in NFKC, which are otherwise 0
 }
 
 type CharsError struct {
@@ -53,8 +154,25 @@ type CharsError struct {
 	err  string
 }
 
+// isPrintable - return true for a printable ASCII character, including space
+func isPrintable(b byte) bool {
+	return b >= ' ' && b < 0x7f
+}
+
+// printableRun - the number of printable ASCII characters at the start of b
+func printableRun(b []byte) int {
+	for i, c := range b {
+		if !isPrintable(c) {
+			return i
+		}
+	}
+	return len(b)
+}
+
 // isText - if 2% of the bytes are non-printable, consider the file to be binary
-func isText(s []byte, n int) bool {
+// when allowLegacy is set, invalid UTF-8 is not counted since it may be text in a legacy encoding
+// ESC is not counted when it starts an ANSI escape sequence, so that terminal captures are text
+func isText(s []byte, n int, allowLegacy bool) bool {
 	const binaryCutoff float32 = 0.02
 	if n < BlockSize {
 		s = s[0:n]
@@ -68,7 +186,10 @@ func isText(s []byte, n int) bool {
 		if i+utf8.UTFMax > len(s) {
 			break // last char may be incomplete - ignore
 		}
-		if c == 0xFFFD || c < ' ' && c != '\n' && c != '\t' && c != '\f' && c != '\r' && c != 0x00 {
+		if c == 0x1b && (s[i+1] == '[' || s[i+1] == ']') {
+			continue
+		}
+		if c == 0xFFFD && !allowLegacy || c < ' ' && c != '\n' && c != '\t' && c != '\f' && c != '\r' && c != 0x00 {
 			bin += 1
 		}
 	}
@@ -79,36 +200,69 @@ func isText(s []byte, n int) bool {
 	return true
 }
 
+// InvalidUtf8 - the number of invalid, overlong and surrogate UTF-8 sequences
+func (sc SpecialChars) InvalidUtf8() uint64 {
+	return sc.Utf8Invalid + sc.Utf8Overlong + sc.Utf8Surrogate
+}
+
+// Controls - the number of control characters other than NUL, tab and line endings
+func (sc SpecialChars) Controls() uint64 {
+	return sc.FormFeed + sc.VerticalTab + sc.Backspace + sc.Escape + sc.Del + sc.OtherC0 + sc.C1
+}
+
+// eolStyle - return a single verdict describing which line endings are used
+func eolStyle(crlf, lf, cr uint64) string {
+	styles := 0
+	verdict := EolNone
+	if lf > 0 {
+		styles++
+		verdict = EolLf
+	}
+	if crlf > 0 {
+		styles++
+		verdict = EolCrlf
+	}
+	if cr > 0 {
+		styles++
+		verdict = EolCr
+	}
+	if styles > 1 {
+		return EolMixed
+	}
+	return verdict
+}
+
 // searchForSpecialChars - search for special chars by incrementally reading in chunks as to not consume too much memory
 // use *bufio.Reader so that either a file or STDIN can be used
-func searchForSpecialChars(filename string, rdr *bufio.Reader, examineBinary bool) (SpecialChars, CharsError) {
-	var (
-		bomUtf8    = [...]byte{0xef, 0xbb, 0xbf}
-		bomUtf16le = [...]byte{0xff, 0xfe}
-		bomUtf16be = [...]byte{0xfe, 0xff}
-	)
+// UTF-16 and UTF-32 input is decoded first unless opts.RawBytes is set, so that each code unit counts as one character
+func searchForSpecialChars(filename string, rdr *bufio.Reader, opts Options) (SpecialChars, CharsError) {
 	var bom8, bom16 uint64
 	var err error
-	var bom []byte
 
-	// check for a BOM
+	// check for a BOM; Bom8 and Bom16 are kept for compatibility with older output
 	// https://en.wikipedia.org/wiki/Byte_order_mark
-	bom, err = rdr.Peek(2)
-	if err == nil {
-		if bytes.HasPrefix(bom, bomUtf16le[:2]) {
-			bom16++
-		} else if bytes.HasPrefix(bom[:2], bomUtf16be[:2]) {
-			bom16++
-		}
-	}
-	if bom16 == 0 {
-		bom, err = rdr.Peek(3)
-		if err == nil {
-			if bytes.HasPrefix(bom, bomUtf8[:3]) {
-				bom8++
-			}
+	peeked, _ := rdr.Peek(bomMaxLength)
+	bom := detectBom(peeked)
+	switch bom {
+	case BomUtf8:
+		bom8++
+	case BomUtf16le, BomUtf16be:
+		bom16++
+	}
+
+	// decode UTF-16 and UTF-32 so that NUL, CRLF and tab are counted in terms of the decoded text
+	var counter *countingReader
+	var decodedFrom string
+	if !opts.RawBytes {
+		var enc encoding.Encoding
+		firstBlock, _ := rdr.Peek(1024)
+		enc, decodedFrom = decoderFor(bom, firstBlock)
+		if enc != nil {
+			counter = &countingReader{r: rdr}
+			rdr = bufio.NewReader(transform.NewReader(counter, enc.NewDecoder()))
 		}
 	}
+	decoded := counter != nil
 
 	// check if file contains binary data
 	var firstBlock []byte
@@ -118,11 +272,38 @@ func searchForSpecialChars(filename string, rdr *bufio.Reader, examineBinary boo
 			_, _ = fmt.Fprintf(os.Stderr, "error: %s\n", err)
 		}
 	}
-	if !examineBinary && !isText(firstBlock, 1024) {
+	if !opts.ExamineBinary && !isText(firstBlock, 1024, opts.DetectEncoding) {
 		return SpecialChars{}, CharsError{code: 2, err: fmt.Sprintf("skipping unwanted binary file: %s", filename)}
 	}
 
-	var tab, lf, crlf, nul, nonAscii, currentNonASCIIStreak, maxConsecutiveNonASCII, bytesRead uint64
+	var tab, lf, crlf, cr, nul, nonAscii, currentNonASCIIStreak, maxConsecutiveNonASCII, bytesRead uint64
+
+	// offset is the position within the examined text; it only matches the file offset when not decoding
+	var offset uint64
+	utf8v := newUtf8Validator()
+	var sample []byte
+
+	loc := newLocator(opts)
+	utf8v.track = loc.enabled
+	loc.recordBom(bom)
+	var crPos, leadPos Location
+	var leadOffset uint64
+
+	// a BOM is not part of the first line, unless it was already removed by decoding
+	lines := newLineTracker(opts.MaxLine)
+	runes := newRuneTracker(opts)
+	scripts := newScriptTracker(opts)
+	nfc := newNormChecker(norm.NFC, "nfc")
+	var nfkc *normChecker // only examined when its counts are reported
+	if opts.Nfkc {
+		checker := newNormChecker(norm.NFKC, "")
+		nfkc = &checker
+	}
+	var controls controlTracker
+	bomSkip := uint64(bomLength(bom))
+	if decoded {
+		bomSkip = 0
+	}
 
 	last := byte(0)
 	buff := make([]byte, BlockSize)
@@ -140,42 +321,194 @@ func searchForSpecialChars(filename string, rdr *bufio.Reader, examineBinary boo
 			return SpecialChars{}, CharsError{code: 1, err: err.Error()}
 		}
 
-		for _, b := range buff {
+		sampleBlock := false
+		for i := 0; i < len(buff); i++ {
+			b := buff[i]
+			if isPrintable(b) && last != '\r' && utf8v.need == 0 && !controls.inSequence() && offset >= bomSkip {
+				// a run of printable ASCII, which is most text, only continues the line and the current word,
+				// so the other trackers do not need to see it
+				run := buff[i : i+printableRun(buff[i:])]
+				loc.advance(b, last, offset)
+				at := loc.pos
+				lines.addRun(&loc, run, at)
+				scripts.addRun(&loc, run, at)
+				if utf8v.first < 0 {
+					nfc.addRun(&loc, run)
+					if nfkc != nil {
+						nfkc.addRun(&loc, run)
+					}
+				}
+				n := uint64(len(run))
+				if loc.tracking {
+					loc.pos = at.advance(n - 1)
+					if loc.context != nil {
+						loc.context.addRun(run[1:])
+					}
+				}
+				offset += n
+				currentNonASCIIStreak, last = 0, run[len(run)-1]
+				i += len(run) - 1
+				continue
+			}
+
+			loc.advance(b, last, offset)
+			if last == '\r' && b != '\n' {
+				lines.end(&loc, crPos) // a lone CR ended the previous line
+			}
+			inSequence := utf8v.need > 0
+			r, complete, failed := utf8v.feed(b, offset)
+			if b != '\r' && b != '\n' && offset >= bomSkip {
+				lines.measure(&loc, r, complete, failed)
+			}
+			for _, at := range utf8v.fails {
+				if at == offset {
+					loc.record("invalidutf8", loc.pos)
+				} else {
+					loc.record("invalidutf8", leadPos)
+				}
+			}
+			utf8v.fails = utf8v.fails[:0]
+			if complete {
+				at := loc.pos
+				if r >= utf8.RuneSelf {
+					at = leadPos
+					runes.add(&loc, r, at, leadOffset)
+					scripts.add(&loc, r, at)
+				} else {
+					scripts.addAscii(&loc, r)
+				}
+				if utf8v.first < 0 { // normalization does not apply to text which is not UTF-8
+					if !nfc.skip(r) {
+						nfc.add(&loc, r, at)
+					}
+					if nfkc != nil && !nfkc.skip(r) {
+						nfkc.add(&loc, r, at)
+					}
+				}
+			}
+			if b >= 0xc0 {
+				leadPos, leadOffset = loc.pos, offset
+			}
+			offset++
+
+			if decoded && b >= 0x80 && b < 0xc0 {
+				last = b
+				continue // decoded text is UTF-8; only count the lead byte of each character
+			}
 			if b > 127 {
+				sampleBlock = true
 				currentNonASCIIStreak++
 				if currentNonASCIIStreak > maxConsecutiveNonASCII {
 					maxConsecutiveNonASCII = currentNonASCIIStreak
 				}
+				if b >= 0xc0 || !inSequence {
+					loc.record("nonascii", loc.pos)
+				}
 			} else {
 				currentNonASCIIStreak = 0
 			}
 
+			if last == '\r' && b != '\n' {
+				cr++ // a lone CR is a classic Mac line ending
+				loc.record("cr", crPos)
+			}
+			if b == '\n' {
+				lines.end(&loc, loc.pos)
+			} else if b != '\r' && offset > bomSkip {
+				lines.add(&loc, b, loc.pos)
+			}
+
+			// tabs and line endings only matter to the control tracker within an escape sequence
+			if b < ' ' && b != '\t' && b != '\n' && b != '\r' || b == 0x7f || controls.inSequence() {
+				controls.add(&loc, b, loc.pos)
+			}
 			if b < ' ' {
 				if b == 0 {
 					nul++
+					loc.record("nul", loc.pos)
 				} else if b == '\n' {
 					lf++
 					if last == '\r' {
 						crlf++
 						lf--
+						loc.record("crlf", crPos)
+					} else {
+						loc.record("lf", loc.pos)
 					}
+				} else if b == '\r' {
+					crPos = loc.pos
 				} else if b == '\t' {
 					tab++
+					loc.record("tab", loc.pos)
 				}
 			} else if b > 127 {
 				nonAscii++
 			}
 			last = b
 		}
+		if opts.DetectEncoding && sampleBlock && len(sample) < encodingSampleSize {
+			sample = append(sample, buff...)
+		}
 
 		if err != nil && err != io.EOF {
 			return SpecialChars{}, CharsError{code: 1, err: err.Error()}
 		}
 	}
+	if last == '\r' {
+		cr++
+		loc.record("cr", crPos)
+		lines.end(&loc, crPos)
+	}
+	noFinalNewline := lines.finish(&loc, last)
+	scripts.finish(&loc)
+	nfc.finish(&loc)
+	var nfkcCodePoints, nfkcLines uint64
+	if nfkc != nil {
+		nfkc.finish(&loc)
+		nfkcCodePoints, nfkcLines = nfkc.codePoints, nfkc.lines
+	}
+	utf8v.finish()
+	for range utf8v.fails {
+		loc.record("invalidutf8", leadPos) // truncated at the end of the input
+	}
+	if utf8v.first >= 0 {
+		// the normalization of text in a legacy encoding, such as Shift_JIS, is not reported
+		nfc.codePoints, nfc.lines, nfkcCodePoints, nfkcLines = 0, 0, 0, 0
+		loc.discard("nfc")
+	}
+	if decoded {
+		bytesRead = counter.n
+	}
+	// words which mix scripts, escape sequences and the like are recorded once they end
+	sort.SliceStable(loc.found, func(i, j int) bool {
+		if loc.found[i].Line != loc.found[j].Line {
+			return loc.found[i].Line < loc.found[j].Line
+		}
+		return loc.found[i].Column < loc.found[j].Column
+	})
 
 	sc := SpecialChars{Filename: filename,
-		Crlf: crlf, Lf: lf, Tab: tab, Bom8: bom8, Bom16: bom16, Nul: nul, NonAscii: nonAscii,
-		MaxConsecutiveNonAscii: maxConsecutiveNonASCII, BytesRead: bytesRead,
+		Crlf: crlf, Lf: lf, Cr: cr, Eol: eolStyle(crlf, lf, cr),
+		Tab: tab, TrailingWhitespace: lines.trailingWs, NoFinalNewline: noFinalNewline, TrailingBlankLines: lines.blankRun,
+		Indent: lines.indentStyle(), IndentWidth: lines.indentWidth(), TabIndentedLines: lines.tabLines,
+		SpaceIndentedLines: lines.spaceLines, MixedIndentedLines: lines.mixedLines,
+		Lines: lines.lines, MaxLineBytes: lines.maxBytes, MaxLineColumns: lines.maxColumns,
+		AverageLineColumns: lines.averageColumns(), LongLines: lines.longLines, Bidi: runes.bidi,
+		Invisible: runes.invisible, Confusable: runes.confusable, Confusables: runes.confusables,
+		Histogram: buildHistogram(runes.counts, opts.HistogramSort),
+		Scripts:   scripts.counts, Blocks: scripts.blocks, MixedScript: scripts.mixedScript,
+		Nfc: nfc.codePoints == 0, NfcCodePoints: nfc.codePoints, NfcLines: nfc.lines,
+		NfkcCodePoints: nfkcCodePoints, NfkcLines: nfkcLines,
+		FormFeed: controls.formFeed, VerticalTab: controls.verticalTab, Backspace: controls.backspace,
+		Escape: controls.escape, AnsiSequences: controls.ansi, Del: controls.del, OtherC0: controls.otherC0, C1: runes.c1,
+		Bom8: bom8, Bom16: bom16, Bom: bom, Nul: nul, NonAscii: nonAscii,
+		MaxConsecutiveNonAscii: maxConsecutiveNonASCII, BytesRead: bytesRead, DecodedFrom: decodedFrom,
+		Utf8Multibyte: utf8v.multibyte, Utf8Invalid: utf8v.invalid, Utf8Overlong: utf8v.overlong,
+		Utf8Surrogate: utf8v.surrogate, FirstInvalidUtf8: utf8v.first,
+		Locations: loc.found, LocationsOmitted: loc.omitted, Context: loc.contextLines(),
+	}
+	if opts.DetectEncoding {
+		sc.Encoding, sc.EncodingConfidence = detectEncoding(sc, sample)
 	}
 	return sc, CharsError{code: 0, err: ""}
 }
@@ -190,6 +523,14 @@ func searchForSpecialChars(filename string, rdr *bufio.Reader, examineBinary boo
 	})
 }*/
 
+// formatCount - render a numeric table cell, optionally with a thousands separator
+func formatCount(n uint64, wantCommas bool) string {
+	if wantCommas {
+		return RenderInteger("#,###.", int64(n))
+	}
+	return strconv.FormatUint(n, 10)
+}
+
 // OutputTextTable - display a text table with each filename and the number of special characters
 func OutputTextTable(allStats []SpecialChars, maxLength int, wantTotals, wantCommas bool) error {
 	if len(allStats) == 0 {
@@ -197,62 +538,160 @@ func OutputTextTable(allStats []SpecialChars, maxLength int, wantTotals, wantCom
 	}
 	// TODO: make this a cmd-line option...
 	// sortByName(allStats)
+	// the encoding column is only shown when -E was used and the fix column with a fix mode
+	// the bidi, invisible, confusable, mixed script, non-NFC and controls columns are only shown when a file contains one of those
+	wantEncoding, wantFix, wantBidi, wantInvisible, wantConfusable, wantMixedScript, wantNfc, wantControls := false, false, false, false, false, false, false, false
+	for _, s := range allStats {
+		wantEncoding = wantEncoding || s.Encoding != ""
+		wantBidi = wantBidi || s.Bidi > 0
+		wantInvisible = wantInvisible || s.Invisible > 0
+		wantConfusable = wantConfusable || s.Confusable > 0
+		wantMixedScript = wantMixedScript || s.MixedScript > 0
+		wantNfc = wantNfc || !s.Nfc
+		wantControls = wantControls || s.Controls() > 0
+		wantFix = wantFix || s.Fix != nil
+	}
+
 	w := bufio.NewWriter(os.Stdout)
 	table := tablewriter.NewWriter(w)
-	table.SetHeader([]string{"filename", "crlf", "lf", "tab", "nul", "bom8", "bom16", "non-ASCII", "max consec N-A", "bytesRead"})
+	header := []string{"filename", "crlf", "lf", "cr", "eol", "tab", "trailing ws", "final nl", "trailing blank", "indent", "maxline", "longlines", "nul", "bom", "non-ASCII", "max consec N-A", "invalid UTF-8", "bytesRead"}
+	if wantBidi {
+		header = append(header, "bidi")
+	}
+	if wantInvisible {
+		header = append(header, "invisible")
+	}
+	if wantConfusable {
+		header = append(header, "confusable")
+	}
+	if wantMixedScript {
+		header = append(header, "mixed script")
+	}
+	if wantNfc {
+		header = append(header, "non-NFC lines")
+	}
+	if wantControls {
+		header = append(header, "controls")
+	}
+	if wantEncoding {
+		header = append(header, "encoding")
+	}
+	if wantFix {
+		header = append(header, "fix", "edits")
+	}
+	table.SetHeader(header)
 
 	var name string
-	var crlf, lf, tab, nul, bom8, bom16, nonAscii, maxConsecutiveNonAscii, bytesRead uint64
+	var crlf, lf, cr, tab, trailingWs, noFinalNewline, trailingBlank, maxLine, longLines, nul, boms, bidi, invisible, confusable, mixedScript, nfcLines, controls, nonAscii, invalidUtf8, bytesRead, fixed, edits uint64
 	for _, s := range allStats {
 		if maxLength == 0 {
 			name = s.Filename
 		} else {
 			name = ellipsis.Shorten(s.Filename, maxLength)
 		}
-		row := []string{name, strconv.FormatUint(s.Crlf, 10), strconv.FormatUint(s.Lf, 10),
-			strconv.FormatUint(s.Tab, 10), strconv.FormatUint(s.Nul, 10), strconv.FormatUint(s.Bom8, 10),
-			strconv.FormatUint(s.Bom16, 10), strconv.FormatUint(s.NonAscii, 10),
-			strconv.FormatUint(s.MaxConsecutiveNonAscii, 10), strconv.FormatUint(s.BytesRead, 10)}
-		if wantCommas {
-			row[1] = RenderInteger("#,###.", int64(s.Crlf))
-			row[2] = RenderInteger("#,###.", int64(s.Lf))
-			row[3] = RenderInteger("#,###.", int64(s.Tab))
-			row[4] = RenderInteger("#,###.", int64(s.Nul))
-			row[5] = RenderInteger("#,###.", int64(s.Bom8))
-			row[6] = RenderInteger("#,###.", int64(s.Bom16))
-			row[7] = RenderInteger("#,###.", int64(s.NonAscii))
-			row[8] = RenderInteger("#,###.", int64(s.MaxConsecutiveNonAscii))
-			row[9] = RenderInteger("#,###.", int64(s.BytesRead))
+		finalNewline := ""
+		if s.NoFinalNewline {
+			finalNewline = "missing"
+		}
+		row := []string{name, formatCount(s.Crlf, wantCommas), formatCount(s.Lf, wantCommas),
+			formatCount(s.Cr, wantCommas), s.Eol, formatCount(s.Tab, wantCommas),
+			formatCount(s.TrailingWhitespace, wantCommas), finalNewline, formatCount(s.TrailingBlankLines, wantCommas),
+			indentCell(s), formatCount(s.MaxLineColumns, wantCommas), formatCount(s.LongLines, wantCommas),
+			formatCount(s.Nul, wantCommas),
+			s.Bom, formatCount(s.NonAscii, wantCommas),
+			formatCount(s.MaxConsecutiveNonAscii, wantCommas), formatCount(s.InvalidUtf8(), wantCommas),
+			formatCount(s.BytesRead, wantCommas)}
+		if wantBidi {
+			row = append(row, formatCount(s.Bidi, wantCommas))
+		}
+		if wantInvisible {
+			row = append(row, formatCount(s.Invisible, wantCommas))
+		}
+		if wantConfusable {
+			row = append(row, formatCount(s.Confusable, wantCommas))
+		}
+		if wantMixedScript {
+			row = append(row, formatCount(s.MixedScript, wantCommas))
+		}
+		if wantNfc {
+			row = append(row, formatCount(s.NfcLines, wantCommas))
+		}
+		if wantControls {
+			row = append(row, formatCount(s.Controls(), wantCommas))
+		}
+		if wantEncoding {
+			row = append(row, fmt.Sprintf("%s (%.0f%%)", s.Encoding, s.EncodingConfidence*100))
+		}
+		if wantFix {
+			if s.Fix == nil {
+				row = append(row, "", "")
+			} else {
+				row = append(row, s.Fix.summary(), formatCount(s.Fix.Edits, wantCommas))
+			}
 		}
 		if wantTotals {
 			crlf += s.Crlf
 			lf += s.Lf
+			cr += s.Cr
 			tab += s.Tab
+			trailingWs += s.TrailingWhitespace
+			if s.NoFinalNewline {
+				noFinalNewline++
+			}
+			trailingBlank += s.TrailingBlankLines
+			maxLine = max(maxLine, s.MaxLineColumns)
+			longLines += s.LongLines
 			nul += s.Nul
-			bom8 += s.Bom8
-			bom16 += s.Bom16
+			if s.Bom != BomNone {
+				boms++
+			}
 			nonAscii += s.NonAscii
-			maxConsecutiveNonAscii += s.MaxConsecutiveNonAscii
+			invalidUtf8 += s.InvalidUtf8()
+			bidi += s.Bidi
+			invisible += s.Invisible
+			confusable += s.Confusable
+			mixedScript += s.MixedScript
+			nfcLines += s.NfcLines
+			controls += s.Controls()
 			bytesRead += s.BytesRead
+			if s.Fix != nil && s.Fix.Edits > 0 {
+				fixed++
+				edits += s.Fix.Edits
+			}
 		}
 		table.Append(row)
 	}
 	if wantTotals {
 		totals := fmt.Sprintf("TOTALS: %d files", len(allStats))
-		row := []string{totals, strconv.FormatUint(crlf, 10), strconv.FormatUint(lf, 10),
-			strconv.FormatUint(tab, 10), strconv.FormatUint(nul, 10), strconv.FormatUint(bom8, 10),
-			strconv.FormatUint(bom16, 10), strconv.FormatUint(nonAscii, 10),
-			"---", strconv.FormatUint(bytesRead, 10)}
-		if wantCommas {
-			row[1] = RenderInteger("#,###.", int64(crlf))
-			row[2] = RenderInteger("#,###.", int64(lf))
-			row[3] = RenderInteger("#,###.", int64(tab))
-			row[4] = RenderInteger("#,###.", int64(nul))
-			row[5] = RenderInteger("#,###.", int64(bom8))
-			row[6] = RenderInteger("#,###.", int64(bom16))
-			row[7] = RenderInteger("#,###.", int64(nonAscii))
-			row[8] = "---"
-			row[9] = RenderInteger("#,###.", int64(bytesRead))
+		row := []string{totals, formatCount(crlf, wantCommas), formatCount(lf, wantCommas),
+			formatCount(cr, wantCommas), eolStyle(crlf, lf, cr), formatCount(tab, wantCommas),
+			formatCount(trailingWs, wantCommas), formatCount(noFinalNewline, wantCommas), formatCount(trailingBlank, wantCommas),
+			"---", formatCount(maxLine, wantCommas), formatCount(longLines, wantCommas), formatCount(nul, wantCommas),
+			formatCount(boms, wantCommas), formatCount(nonAscii, wantCommas),
+			"---", formatCount(invalidUtf8, wantCommas), formatCount(bytesRead, wantCommas)}
+		if wantBidi {
+			row = append(row, formatCount(bidi, wantCommas))
+		}
+		if wantInvisible {
+			row = append(row, formatCount(invisible, wantCommas))
+		}
+		if wantConfusable {
+			row = append(row, formatCount(confusable, wantCommas))
+		}
+		if wantMixedScript {
+			row = append(row, formatCount(mixedScript, wantCommas))
+		}
+		if wantNfc {
+			row = append(row, formatCount(nfcLines, wantCommas))
+		}
+		if wantControls {
+			row = append(row, formatCount(controls, wantCommas))
+		}
+		if wantEncoding {
+			row = append(row, "---")
+		}
+		if wantFix {
+			row = append(row, formatCount(fixed, wantCommas), formatCount(edits, wantCommas))
 		}
 		table.Append(row)
 	}
@@ -260,6 +699,14 @@ func OutputTextTable(allStats []SpecialChars, maxLength int, wantTotals, wantCom
 	return w.Flush()
 }
 
+// indentCell - the indentation style, along with its width for files indented with spaces
+func indentCell(s SpecialChars) string {
+	if s.Indent == IndentSpaces && s.IndentWidth > 0 {
+		return fmt.Sprintf("%s (%d)", s.Indent, s.IndentWidth)
+	}
+	return s.Indent
+}
+
 // OutputFailedFileList - only display a list of file names that have failed when using -F cmd line option
 func OutputFailedFileList(allStats []SpecialChars) {
 	if len(allStats) == 0 {
@@ -288,7 +735,12 @@ func GetJSON(allStats []SpecialChars) string {
 }
 
 // ProcessGlob - process all files matching the file-glob
-func ProcessGlob(globArg string, allStats *[]SpecialChars, examineBinary bool, excludeMatched *regexp.Regexp, fail string) uint64 {
+func ProcessGlob(globArg string, allStats *[]SpecialChars, opts Options, excludeMatched *regexp.Regexp, fail string) uint64 {
+	return ProcessFileList(ExpandGlob(globArg), allStats, opts, excludeMatched, fail)
+}
+
+// ExpandGlob - return all files matching the file-glob, matching case-insensitively as is expected on Windows
+func ExpandGlob(globArg string) []string {
 	var err error
 	anyCase := CaseInsensitive(globArg)
 	if len(globArg) > 0 && len(anyCase) == 0 {
@@ -302,12 +754,18 @@ func ProcessGlob(globArg string, allStats *[]SpecialChars, examineBinary bool, e
 	if len(globFiles) == 0 {
 		globFiles = []string{anyCase}
 	}
-	return ProcessFileList(globFiles, allStats, examineBinary, excludeMatched, fail)
+	return globFiles
 }
 
-// ProcessFileList - process a list of filenames
-func ProcessFileList(globFiles []string, allStats *[]SpecialChars, examineBinary bool, excludeMatched *regexp.Regexp, fail string) uint64 {
-	var file *os.File
+// ProcessFileList - process a list of filenames; directories are walked when opts.Recursive is set
+func ProcessFileList(globFiles []string, allStats *[]SpecialChars, opts Options, excludeMatched *regexp.Regexp, fail string) uint64 {
+	filter, err := newFileFilter(opts)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		return 0
+	}
+
+	var allFiles []string
 	for _, filename := range globFiles {
 		info, err := os.Stat(filename)
 		if err != nil {
@@ -316,35 +774,30 @@ func ProcessFileList(globFiles []string, allStats *[]SpecialChars, examineBinary
 		}
 
 		if info.IsDir() {
+			if opts.Recursive {
+				allFiles = append(allFiles, walkDirectory(filename, opts, filter)...)
+			}
 			// fmt.Println("skipping directory:", filename)
 			continue
 		}
+		if filter.wantFile(filepath.ToSlash(filename)) {
+			allFiles = append(allFiles, filename)
+		}
+	}
+
+	var wanted []string
+	for _, filename := range allFiles {
 		if excludeMatched != nil {
 			if excludeMatched.Match([]byte(filename)) {
 				// fmt.Println("excluding file:", filename)
 				continue
 			}
 		}
-
-		// fmt.Println("checking file:", filename)
-		file, err = os.Open(filename)
-		if err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "%s\n", err)
-			continue
-		}
-
-		reader := bufio.NewReader(file)
-		stats, charsErr := searchForSpecialChars(filename, reader, examineBinary)
-		_ = file.Close()
-		if charsErr.code == 1 {
-			// output error message except for an unwanted binary file
-			_, _ = fmt.Fprintf(os.Stderr, "error #%d: %s\n", charsErr.code, charsErr.err)
-			continue
-		} else if charsErr.code != 0 {
-			continue
-		}
-		*allStats = append(*allStats, stats)
+		wanted = append(wanted, filename)
 	}
+
+	// workers never touch allStats; the results are appended here, in order, once all files are examined
+	*allStats = append(*allStats, scanFiles(wanted, opts)...)
 	if len(fail) > 0 {
 		return GetFailures(fail, allStats)
 	}
@@ -352,11 +805,11 @@ func ProcessFileList(globFiles []string, allStats *[]SpecialChars, examineBinary
 }
 
 // ProcessStdin - read a file stream directly from STDIN
-func ProcessStdin(allStats *[]SpecialChars, examineBinary bool, fail string) (uint64, CharsError) {
+func ProcessStdin(allStats *[]SpecialChars, opts Options, fail string) (uint64, CharsError) {
 	var charsErr CharsError
 
 	reader := bufio.NewReader(os.Stdin)
-	stats, charsErr := searchForSpecialChars("STDIN", reader, examineBinary)
+	stats, charsErr := searchForSpecialChars("STDIN", reader, opts)
 	if charsErr.code != 0 {
 		return 0, charsErr
 	}
@@ -382,8 +835,32 @@ func GetFailures(commaList string, allStats *[]SpecialChars) uint64 {
 				failed += entry.Crlf
 			case "lf":
 				failed += entry.Lf
+			case "cr":
+				failed += entry.Cr
+			case "mixed":
+				if entry.Eol == EolMixed {
+					failed++
+				}
 			case "tab":
 				failed += entry.Tab
+			case "trailingws":
+				failed += entry.TrailingWhitespace
+			case "nofinalnl":
+				if entry.NoFinalNewline {
+					failed++
+				}
+			case "trailingblank":
+				failed += entry.TrailingBlankLines
+			case "mixedindent":
+				if entry.Indent == IndentMixed {
+					failed++
+				}
+			case "maxline":
+				if entry.LongLines > 0 {
+					failed++
+				}
+			case "longlines":
+				failed += entry.LongLines
 			case "bom8":
 				failed += entry.Bom8
 			case "bom16":
@@ -394,7 +871,43 @@ func GetFailures(commaList string, allStats *[]SpecialChars) uint64 {
 				failed += entry.MaxConsecutiveNonAscii
 			case "nul":
 				failed += entry.Nul
+			case "invalidutf8":
+				failed += entry.InvalidUtf8()
+			case "bidi":
+				failed += entry.Bidi
+			case "invisible":
+				failed += entry.Invisible
+			case "confusable":
+				failed += entry.Confusable
+			case "mixedscript":
+				failed += entry.MixedScript
+			case "nfc":
+				failed += entry.NfcCodePoints
+			case "ff":
+				failed += entry.FormFeed
+			case "vt":
+				failed += entry.VerticalTab
+			case "bs":
+				failed += entry.Backspace
+			case "esc":
+				failed += entry.Escape
+			case "ansi":
+				failed += entry.AnsiSequences
+			case "del":
+				failed += entry.Del
+			case "c0":
+				failed += entry.OtherC0
+			case "c1":
+				failed += entry.C1
+			case "controls":
+				failed += entry.Controls()
 			default:
+				if isBomClass(class) {
+					if bomMatchesClass(entry.Bom, class) {
+						failed++
+					}
+					continue
+				}
 				fmt.Fprintf(os.Stderr, "Unknown character passed to -f: %s\n", class)
 			}
 		}
@@ -421,9 +934,33 @@ func SortByColumn(entries []SpecialChars, column string) {
 		"lf": func(i, j int) bool {
 			return entries[i].Lf < entries[j].Lf
 		},
+		"cr": func(i, j int) bool {
+			return entries[i].Cr < entries[j].Cr
+		},
+		"mixed": func(i, j int) bool {
+			return entries[i].Eol != EolMixed && entries[j].Eol == EolMixed
+		},
 		"tab": func(i, j int) bool {
 			return entries[i].Tab < entries[j].Tab
 		},
+		"trailingws": func(i, j int) bool {
+			return entries[i].TrailingWhitespace < entries[j].TrailingWhitespace
+		},
+		"nofinalnl": func(i, j int) bool {
+			return !entries[i].NoFinalNewline && entries[j].NoFinalNewline
+		},
+		"trailingblank": func(i, j int) bool {
+			return entries[i].TrailingBlankLines < entries[j].TrailingBlankLines
+		},
+		"mixedindent": func(i, j int) bool {
+			return entries[i].Indent != IndentMixed && entries[j].Indent == IndentMixed
+		},
+		"maxline": func(i, j int) bool {
+			return entries[i].MaxLineColumns < entries[j].MaxLineColumns
+		},
+		"longlines": func(i, j int) bool {
+			return entries[i].LongLines < entries[j].LongLines
+		},
 		"nul": func(i, j int) bool {
 			return entries[i].Nul < entries[j].Nul
 		},
@@ -433,17 +970,50 @@ func SortByColumn(entries []SpecialChars, column string) {
 		"bom16": func(i, j int) bool {
 			return entries[i].Bom16 < entries[j].Bom16
 		},
+		"bom": func(i, j int) bool {
+			return entries[i].Bom < entries[j].Bom
+		},
 		"nonascii": func(i, j int) bool {
 			return entries[i].NonAscii < entries[j].NonAscii
 		},
 		"maxconsec": func(i, j int) bool {
 			return entries[i].MaxConsecutiveNonAscii < entries[j].MaxConsecutiveNonAscii
 		},
+		"invalidutf8": func(i, j int) bool {
+			return entries[i].InvalidUtf8() < entries[j].InvalidUtf8()
+		},
+		"bidi": func(i, j int) bool {
+			return entries[i].Bidi < entries[j].Bidi
+		},
+		"invisible": func(i, j int) bool {
+			return entries[i].Invisible < entries[j].Invisible
+		},
+		"confusable": func(i, j int) bool {
+			return entries[i].Confusable < entries[j].Confusable
+		},
+		"mixedscript": func(i, j int) bool {
+			return entries[i].MixedScript < entries[j].MixedScript
+		},
+		"nfc": func(i, j int) bool {
+			return entries[i].NfcCodePoints < entries[j].NfcCodePoints
+		},
+		"controls": func(i, j int) bool {
+			return entries[i].Controls() < entries[j].Controls()
+		},
 		"bytesread": func(i, j int) bool {
 			return entries[i].BytesRead < entries[j].BytesRead
 		},
 	}
 
+	// the other byte order mark classes sort the files with a matching BOM last
+	for _, class := range bomClassNames() {
+		if _, ok := compareFuncs[class]; !ok {
+			compareFuncs[class] = func(i, j int) bool {
+				return !bomMatchesClass(entries[i].Bom, class) && bomMatchesClass(entries[j].Bom, class)
+			}
+		}
+	}
+
 	// Get the comparison function for the specified column
 	compareFunc, ok := compareFuncs[strings.ToLower(column)]
 	if !ok {
@@ -451,13 +1021,17 @@ func SortByColumn(entries []SpecialChars, column string) {
 		compareFunc = compareFuncs["filename"]
 	}
 
-	// Sort the entries
-	sort.Slice(entries, compareFunc)
+	// Sort the entries; a stable sort keeps ties in the order the files were given
+	sort.SliceStable(entries, compareFunc)
 }
 
-// GetValidSortColumns - returns a list of valid column names for sorting
+// GetValidSortColumns - returns a list of valid column names for sorting, including each byte order mark class
 func GetValidSortColumns() []string {
-	return []string{
-		"filename", "crlf", "lf", "tab", "nul", "bom8", "bom16", "nonascii", "maxconsec", "bytesread",
+	columns := []string{
+		"filename", "crlf", "lf", "cr", "mixed", "tab", "trailingws", "nofinalnl", "trailingblank", "mixedindent",
+		"maxline", "longlines", "nul", "bom",
 	}
+	columns = append(columns, bomClassNames()...)
+	return append(columns, "nonascii", "maxconsec", "invalidutf8", "bidi", "invisible",
+		"confusable", "mixedscript", "nfc", "controls", "bytesread")
 }
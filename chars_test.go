@@ -0,0 +1,76 @@
+package chars
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"golang.org/x/text/encoding/unicode"
+)
+
+// chunkedInput - text with something for each tracker to find, after enough lines that it is not
+// part of the first block peeked at to detect binary files and encodings
+func chunkedInput() string {
+	var sb strings.Builder
+	for i := range 100 {
+		_, _ = fmt.Fprintf(&sb, "line %03d\n", i)
+	}
+	sb.WriteString("a\r\nb\rc\td  \n")
+	sb.WriteString("  \tmixed indent\n")
+	sb.WriteString("naïve cafe\u0301 p\u0430yp\u0430l zero\u200bwidth \u202eevil\u202c\n")
+	sb.WriteString("\x1b[31mred\x1b[0m \x1b]0;title\x07 page\x0c\u0085\n")
+	sb.WriteString("\ufb01le name\n\n \n")
+	return sb.String()
+}
+
+// TestSearchForSpecialCharsChunked - the counts, locations and context lines must not depend on how
+// much input each read returns, since state such as a CR or a UTF-8 character spans reads
+func TestSearchForSpecialCharsChunked(t *testing.T) {
+	text := chunkedInput()
+	utf16, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().String(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs := map[string]string{
+		"utf8":    text,
+		"invalid": strings.Replace(text, "\ufb01le", "bad \xff\xc3 x\xe2\x82\n\ufb01le", 1),
+		"utf16le": utf16,
+	}
+	opts := Options{Locations: true, Context: true, ContextLines: 1, Confusables: true, Histogram: true, HistogramSort: HistogramSortCount,
+		Scripts: true, Nfkc: true, LocationClasses: strings.Split("crlf,cr,tab,trailingws,trailingblank,mixedindent,"+
+			"nonascii,invalidutf8,bidi,invisible,confusable,mixedscript,nfc,controls,ansi,ff,esc,c1", ",")}
+
+	for name, input := range inputs {
+		whole, cerr := searchForSpecialChars(name, bufio.NewReader(strings.NewReader(input)), opts)
+		if cerr.code != 0 {
+			t.Fatalf("%s: %s", name, cerr.err)
+		}
+		if whole.Crlf != 1 || whole.Cr != 1 || whole.AnsiSequences != 3 || whole.Bidi != 2 || whole.Invisible != 1 ||
+			whole.MixedScript != 1 || whole.TrailingBlankLines != 2 {
+			t.Errorf("%s: unexpected counts %+v", name, whole)
+		}
+		if name == "invalid" && (whole.InvalidUtf8() != 3 || whole.NfcLines != 0 || whole.NfkcLines != 0) {
+			t.Errorf("%s: found %d invalid UTF-8 sequences, want 3, and %d nfc and %d nfkc lines, want none", name,
+				whole.InvalidUtf8(), whole.NfcLines, whole.NfkcLines)
+		}
+		if name != "invalid" && (whole.NfcLines != 1 || whole.NfkcLines != 2) {
+			t.Errorf("%s: found %d nfc and %d nfkc lines, want 1 and 2", name, whole.NfcLines, whole.NfkcLines)
+		}
+
+		readers := map[string]func(io.Reader) io.Reader{"one byte": iotest.OneByteReader, "half": iotest.HalfReader}
+		for readerName, newReader := range readers {
+			chunked, cerr := searchForSpecialChars(name, bufio.NewReader(newReader(bytes.NewReader([]byte(input)))), opts)
+			if cerr.code != 0 {
+				t.Fatalf("%s %s: %s", name, readerName, cerr.err)
+			}
+			if !reflect.DeepEqual(whole, chunked) {
+				t.Errorf("%s: reading %s at a time\n got %+v\nwant %+v", name, readerName, chunked, whole)
+			}
+		}
+	}
+}
@@ -19,6 +19,18 @@ import (
 	"strings"
 )
 
+// globList - a cmd-line option which may be given more than once
+type globList []string
+
+func (g *globList) String() string {
+	return strings.Join(*g, ",")
+}
+
+func (g *globList) Set(value string) error {
+	*g = append(*g, value)
+	return nil
+}
+
 // Usage - display help when no cmd-line args given
 func Usage() {
 	_, _ = fmt.Fprintf(os.Stderr, "\n")
@@ -32,7 +44,8 @@ func Usage() {
 	_, _ = fmt.Fprintf(os.Stderr, "\n")
 	_, _ = fmt.Fprintln(os.Stderr, "Notes:")
 	_, _ = fmt.Fprintln(os.Stderr, "Use - to read a file from STDIN")
-	_, _ = fmt.Fprintf(os.Stderr, "On Windows, try: %s *  -or-  %s */*  -or-  %s */*/*\n", chars.PgmName, chars.PgmName, chars.PgmName)
+	_, _ = fmt.Fprintf(os.Stderr, "Use -r to examine all files below a directory, such as: %s -r --include '*.go' .\n", chars.PgmName)
+	_, _ = fmt.Fprintf(os.Stderr, "On Windows, try: %s *  -or-  %s */*  -or-  %s -r .\n", chars.PgmName, chars.PgmName, chars.PgmName)
 	_, _ = fmt.Fprintf(os.Stderr, "\n")
 }
 
@@ -43,11 +56,45 @@ func main() {
 	argsMaxLength := flag.Int("l", 0, "shorten files names to a maximum of this length")
 	argsJSON := flag.Bool("j", false, "output results in JSON format; can't be used with -l; does not honor -t or -c")
 	argsVersion := flag.Bool("v", false, "display version and then exit")
-	argsFail := flag.String("f", "", "fail with OS exit code=100 if any of the included characters exist; ex: -f crlf,nul,bom8,nonascii")
+	argsFail := flag.String("f", "", "fail with OS exit code=100 if any of the included characters exist; ex: -f crlf,cr,mixed,nul,bom8,nonascii,invalidutf8")
 	argsFailedFileList := flag.Bool("F", false, "when used with -f, only display a list of failed files, one per line")
 	argsTotals := flag.Bool("t", false, "append a row which includes a total for each column")
 	argsComma := flag.Bool("c", false, "add comma thousands separator to numeric values")
-	argsSortBy := flag.String("s", "filename", "sort output by column: filename crlf lf tab nul bom8 bom16 nonascii maxconsec bytesread")
+	argsRaw := flag.Bool("raw", false, "count raw bytes instead of decoding UTF-16 and UTF-32 input")
+	var argsDetectEncoding bool
+	flag.BoolVar(&argsDetectEncoding, "E", false, "guess the character encoding of each file, with a confidence score")
+	flag.BoolVar(&argsDetectEncoding, "detect-encoding", false, "same as -E")
+	argsLocations := flag.Bool("locations", false, "display the line and column of each character matched by -f, compiler-style; included in -j output")
+	argsMaxLocations := flag.Int("max-locations", chars.DefaultMaxLocations, "when used with --locations, the maximum number of locations reported per file; 0 is unlimited")
+	argsContext := flag.Int("context", -1, "display this many lines around each location with invisible characters made visible; implies --locations")
+	argsRecursive := flag.Bool("r", false, "recursively examine all files below each directory")
+	var argsInclude, argsExcludeGlob globList
+	flag.Var(&argsInclude, "include", "only examine files matching this glob, such as *.go or docs/**/*.md; may be repeated")
+	flag.Var(&argsExcludeGlob, "exclude", "skip files and directories matching this glob; may be repeated")
+	argsMaxDepth := flag.Int("max-depth", 0, "when used with -r, descend at most this many directory levels; 0 is unlimited")
+	argsHidden := flag.Bool("hidden", false, "when used with -r, also examine files and directories starting with a dot")
+	argsFollow := flag.Bool("follow-symlinks", false, "when used with -r, follow symbolic links; loops are detected and skipped")
+	argsNoIgnore := flag.Bool("no-ignore", false, "when used with -r, do not honor .gitignore, .git/info/exclude, the global git excludes file or .charsignore")
+	argsWorkers := flag.Int("p", 0, "number of files to examine in parallel; 0 uses one per CPU; output order is not affected")
+	argsFixEol := flag.String("fix-eol", "", "rewrite files so that every line ending, including a lone cr, is either lf or crlf; binary files are skipped")
+	argsStripBom := flag.Bool("strip-bom", false, "rewrite files which start with a byte order mark without it")
+	argsAddBom := flag.String("add-bom", "", "rewrite files which do not start with a byte order mark to start with one; only utf8 is supported")
+	argsToUtf8 := flag.Bool("to-utf8", false, "rewrite UTF-16 and UTF-32 files, and files in the --from encoding, as UTF-8")
+	argsFrom := flag.String("from", "", "when used with --to-utf8, the encoding of files which are not valid UTF-8, such as windows-1252")
+	argsLossy := flag.Bool("lossy", false, "when used with --to-utf8, rewrite files even when some characters can not be converted")
+	argsExpandTabs := flag.Int("expand-tabs", 0, "rewrite files replacing each tab with spaces up to the next tab stop, which are this many columns apart")
+	argsUnexpandTabs := flag.Int("unexpand-tabs", 0, "rewrite the indentation of each line using tabs this many columns wide; other whitespace is left alone")
+	argsStripTrailingWs := flag.Bool("strip-trailing-ws", false, "rewrite files removing spaces and tabs from the end of each line")
+	argsSuggest := flag.Bool("suggest", false, "display a unified diff of each file against its normal form: lf line endings, no bom, no trailing whitespace and a final newline; files are not changed; exit code=100 if any file would change")
+	argsDryRun := flag.Bool("dry-run", false, "when used with a fix mode, display what would change without writing any files")
+	argsPreserveMtime := flag.Bool("preserve-mtime", false, "when used with a fix mode, keep the modification time of rewritten files")
+	argsMaxLine := flag.Int("max-line", 0, "count lines wider than this many display columns as longlines; tabs advance to the next multiple of 8")
+	argsConfusables := flag.Bool("confusables", false, "display each non-ASCII character which looks like an ASCII character, such as Cyrillic a")
+	argsHistogram := flag.Bool("histogram", false, "display how many times each non-ASCII character was found, with its code point, name and general category")
+	argsHistogramSort := flag.String("histogram-sort", chars.HistogramSortCount, "when used with --histogram, sort characters by: count codepoint")
+	argsScripts := flag.Bool("scripts", false, "also display the number of characters of each Unicode script, such as Latin or Cyrillic, and of each block")
+	argsNormalize := flag.String("normalize", "", "rewrite files in this Unicode normalization form: nfc nfkc")
+	argsSortBy := flag.String("s", "filename", "sort output by column: "+strings.Join(chars.GetValidSortColumns(), " "))
 
 	flag.Usage = Usage
 	flag.Parse()
@@ -79,6 +126,56 @@ func main() {
 		os.Exit(3)
 	}
 
+	fixEol := strings.ToLower(*argsFixEol)
+	if len(fixEol) > 0 && !chars.ValidEol(fixEol) {
+		_, _ = fmt.Fprintf(os.Stderr, "Invalid --fix-eol: %s\nValid line endings are: %s, %s\n", *argsFixEol, chars.EolLf, chars.EolCrlf)
+		os.Exit(3)
+	}
+
+	addBom := strings.ToLower(*argsAddBom)
+	if len(addBom) > 0 && addBom != "utf8" && addBom != "utf-8" {
+		_, _ = fmt.Fprintf(os.Stderr, "Invalid --add-bom: %s\nOnly utf8 is supported\n", *argsAddBom)
+		os.Exit(3)
+	}
+	if len(addBom) > 0 && *argsStripBom {
+		_, _ = fmt.Fprintf(os.Stderr, "--strip-bom and --add-bom are mutually exclusive")
+		os.Exit(2)
+	}
+
+	fromEncoding := *argsFrom
+	if len(fromEncoding) > 0 {
+		canonical, err := chars.LookupEncoding(fromEncoding)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Invalid --from: %s\n", err)
+			os.Exit(3)
+		}
+		fromEncoding = canonical
+	}
+
+	if *argsExpandTabs > 0 && *argsUnexpandTabs > 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "--expand-tabs and --unexpand-tabs are mutually exclusive")
+		os.Exit(2)
+	}
+	for _, size := range []int{*argsExpandTabs, *argsUnexpandTabs} {
+		if size < 0 || size > chars.MaxTabSize {
+			_, _ = fmt.Fprintf(os.Stderr, "Invalid tab size: %d\nValid sizes are 1 to %d\n", size, chars.MaxTabSize)
+			os.Exit(3)
+		}
+	}
+
+	histogramSort := strings.ToLower(*argsHistogramSort)
+	if !chars.ValidHistogramSort(histogramSort) {
+		_, _ = fmt.Fprintf(os.Stderr, "Invalid --histogram-sort: %s\nValid orders are: %s, %s\n", *argsHistogramSort,
+			chars.HistogramSortCount, chars.HistogramSortCodePoint)
+		os.Exit(3)
+	}
+
+	normalize := strings.ToLower(*argsNormalize)
+	if len(normalize) > 0 && !chars.ValidNormalForm(normalize) {
+		_, _ = fmt.Fprintf(os.Stderr, "Invalid --normalize: %s\nValid forms are: %s, %s\n", *argsNormalize, chars.NormNfc, chars.NormNfkc)
+		os.Exit(3)
+	}
+
 	var err error
 	var excludeFiles *regexp.Regexp
 	if len(*argsExclude) > 0 {
@@ -89,25 +186,68 @@ func main() {
 		}
 	}
 
+	for _, globs := range [][]string{argsInclude, argsExcludeGlob} {
+		if err = chars.ValidateGlobs(globs); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Invalid glob: %s\n", err)
+			os.Exit(4)
+		}
+	}
+
 	// no cmd-line filenames were passed, so read from STDIN
 	if len(allGlobs) == 0 {
 		allGlobs = []string{"-"}
 	}
 
+	opts := chars.Options{ExamineBinary: *argsBinary, RawBytes: *argsRaw, DetectEncoding: argsDetectEncoding,
+		Locations: *argsLocations || *argsContext >= 0, MaxLocations: *argsMaxLocations,
+		Context: *argsContext >= 0, ContextLines: *argsContext,
+		Recursive: *argsRecursive, Include: argsInclude, Exclude: argsExcludeGlob, MaxDepth: *argsMaxDepth,
+		Hidden: *argsHidden, FollowSymlinks: *argsFollow, NoIgnore: *argsNoIgnore,
+		Workers: *argsWorkers, FixEol: fixEol, DryRun: *argsDryRun, PreserveMtime: *argsPreserveMtime,
+		StripBom: *argsStripBom, AddBom: len(addBom) > 0, ToUtf8: *argsToUtf8, FromEncoding: fromEncoding, Lossy: *argsLossy,
+		ExpandTabs: *argsExpandTabs, UnexpandTabs: *argsUnexpandTabs, StripTrailingWs: *argsStripTrailingWs,
+		Suggest: *argsSuggest, MaxLine: *argsMaxLine, Confusables: *argsConfusables,
+		Histogram: *argsHistogram, HistogramSort: histogramSort, Scripts: *argsScripts,
+		Normalize: normalize, Nfkc: *argsJSON || normalize == chars.NormNfkc}
+	if opts.Fixing() || opts.Suggest {
+		for _, fileSelection := range allGlobs {
+			if fileSelection == "-" {
+				_, _ = fmt.Fprintf(os.Stderr, "fix modes and --suggest can not be used with STDIN\n")
+				os.Exit(2)
+			}
+		}
+	}
+	if len(*argsFail) > 0 {
+		opts.LocationClasses = strings.Split(*argsFail, ",")
+		for _, class := range opts.LocationClasses {
+			class = strings.ToLower(strings.TrimSpace(class))
+			if (class == "maxline" || class == "longlines") && *argsMaxLine <= 0 {
+				_, _ = fmt.Fprintf(os.Stderr, "warning: -f %s never fails without --max-line\n", class)
+			}
+		}
+	}
+
 	// allStats will be modified in-place by one of the two functions below
+	// files are gathered first so that they can be examined concurrently, keeping stdin in its place
 	var allStats []chars.SpecialChars
+	var allFiles []string
 	var failed, current uint64
 	for _, fileSelection := range allGlobs {
 		if fileSelection == "-" {
-			current, _ = chars.ProcessStdin(&allStats, *argsBinary, *argsFail)
-		} else {
-			if runtime.GOOS == "windows" {
-				current = chars.ProcessGlob(fileSelection, &allStats, *argsBinary, excludeFiles, *argsFail)
-			} else {
-				current = chars.ProcessFileList([]string{fileSelection}, &allStats, *argsBinary, excludeFiles, *argsFail)
+			if len(allFiles) > 0 {
+				failed += chars.ProcessFileList(allFiles, &allStats, opts, excludeFiles, *argsFail)
+				allFiles = nil
 			}
+			current, _ = chars.ProcessStdin(&allStats, opts, *argsFail)
+			failed += current
+		} else if runtime.GOOS == "windows" {
+			allFiles = append(allFiles, chars.ExpandGlob(fileSelection)...)
+		} else {
+			allFiles = append(allFiles, fileSelection)
 		}
-		failed += current
+	}
+	if len(allFiles) > 0 {
+		failed += chars.ProcessFileList(allFiles, &allStats, opts, excludeFiles, *argsFail)
 	}
 
 	// Sort the results by the specified column
@@ -115,23 +255,56 @@ func main() {
 		chars.SortByColumn(allStats, sortColumn)
 	}
 
-	// output results to either JSON or text table
+	// output results to either JSON, a diff or text table
+	var suggested uint64
 	if *argsJSON {
+		for _, s := range allStats {
+			if len(s.Suggestion) > 0 {
+				suggested++
+			}
+		}
 		_, err := fmt.Println(chars.GetJSON(allStats))
 		if err != nil {
 			os.Exit(5)
 		}
 	} else if *argsFailedFileList && len(*argsFail) > 0 && failed > 0 {
 		chars.OutputFailedFileList(allStats)
+	} else if opts.Suggest {
+		suggested, err = chars.OutputSuggestions(allStats)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(6)
+		}
+	} else if opts.Locations {
+		err := chars.OutputLocations(allStats)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(6)
+		}
+	} else if opts.Histogram {
+		err := chars.OutputHistogram(allStats, *argsMaxLength, *argsComma)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(6)
+		}
+	} else if *argsConfusables {
+		err := chars.OutputConfusables(allStats)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(6)
+		}
 	} else {
 		err := chars.OutputTextTable(allStats, *argsMaxLength, *argsTotals, *argsComma)
+		if err == nil && *argsScripts {
+			err = chars.OutputScripts(allStats, *argsMaxLength, *argsComma)
+		}
 		if err != nil {
 			_, _ = fmt.Fprintf(os.Stderr, "Error: %s\n", err)
 			os.Exit(6)
 		}
 	}
 
-	if len(*argsFail) > 0 && failed > 0 {
+	if len(*argsFail) > 0 && failed > 0 || suggested > 0 {
 		os.Exit(100)
 	}
 }
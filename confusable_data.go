@@ -0,0 +1,279 @@
+package chars
+
+/*
+confusable_data.go
+
+Non-ASCII characters which look like a single printable ASCII character, taken from the
+confusables.txt data of Unicode Technical Standard #39; characters which are only confusable
+with a sequence of characters, such as U+00C6 with "AE", are not included.
+https://www.unicode.org/Public/security/latest/confusables.txt
+*/
+
+// confusableAscii - each character and the ASCII character it can be mistaken for; following
+// confusables.txt, the prototype for characters resembling I, 1 and | is l and for 0 it is O
+var confusableAscii = map[rune]byte{
+	0x00b4: '\'', 0x00b8: ',', 0x00d7: 'x', 0x00fe: 'p', 0x0131: 'i', 0x017f: 'f',
+	0x0184: 'b', 0x018d: 'g', 0x0192: 'f', 0x0196: 'l', 0x01a6: 'R', 0x01a7: '2',
+	0x01b7: '3', 0x01bc: '5', 0x01bd: 's', 0x01bf: 'p', 0x01c0: 'l', 0x01c3: '!',
+	0x021c: '3', 0x0222: '8', 0x0223: '8', 0x0241: '?', 0x0251: 'a', 0x0261: 'g',
+	0x0263: 'y', 0x0269: 'i', 0x026a: 'i', 0x026f: 'w', 0x028b: 'u', 0x028f: 'y',
+	0x0294: '?', 0x02b9: '\'', 0x02bb: '\'', 0x02bc: '\'', 0x02bd: '\'', 0x02be: '\'',
+	0x02c2: '<', 0x02c3: '>', 0x02c4: '^', 0x02c6: '^', 0x02c8: '\'', 0x02ca: '\'',
+	0x02cb: '\'', 0x02d0: ':', 0x02d7: '-', 0x02db: 'i', 0x02dc: '~', 0x02f4: '\'',
+	0x02f8: ':', 0x0374: '\'', 0x037a: 'i', 0x037e: ';', 0x037f: 'J', 0x0384: '\'',
+	0x0391: 'A', 0x0392: 'B', 0x0395: 'E', 0x0396: 'Z', 0x0397: 'H', 0x0399: 'l',
+	0x039a: 'K', 0x039c: 'M', 0x039d: 'N', 0x039f: 'O', 0x03a1: 'P', 0x03a4: 'T',
+	0x03a5: 'Y', 0x03a7: 'X', 0x03b1: 'a', 0x03b3: 'y', 0x03b9: 'i', 0x03bd: 'v',
+	0x03bf: 'o', 0x03c1: 'p', 0x03c3: 'o', 0x03c5: 'u', 0x03d2: 'Y', 0x03dc: 'F',
+	0x03e8: '2', 0x03ec: '6', 0x03ed: 'o', 0x03f1: 'p', 0x03f2: 'c', 0x03f3: 'j',
+	0x03f8: 'p', 0x03f9: 'C', 0x03fa: 'M', 0x0405: 'S', 0x0406: 'l', 0x0408: 'J',
+	0x0410: 'A', 0x0412: 'B', 0x0415: 'E', 0x0417: '3', 0x041a: 'K', 0x041c: 'M',
+	0x041d: 'H', 0x041e: 'O', 0x0420: 'P', 0x0421: 'C', 0x0422: 'T', 0x0423: 'Y',
+	0x0425: 'X', 0x042c: 'b', 0x0430: 'a', 0x0431: '6', 0x0433: 'r', 0x0435: 'e',
+	0x043e: 'o', 0x0440: 'p', 0x0441: 'c', 0x0443: 'y', 0x0445: 'x', 0x0448: 'w',
+	0x0455: 's', 0x0456: 'i', 0x0458: 'j', 0x0461: 'w', 0x0474: 'V', 0x0475: 'v',
+	0x04ae: 'Y', 0x04af: 'y', 0x04bb: 'h', 0x04bd: 'e', 0x04c0: 'l', 0x04cf: 'l',
+	0x04e0: '3', 0x0501: 'd', 0x050c: 'G', 0x051b: 'q', 0x051c: 'W', 0x051d: 'w',
+	0x054d: 'U', 0x054f: 'S', 0x0555: 'O', 0x055a: '\'', 0x055d: '\'', 0x0561: 'w',
+	0x0563: 'q', 0x0566: 'q', 0x0570: 'h', 0x0578: 'n', 0x057c: 'n', 0x057d: 'u',
+	0x0581: 'g', 0x0582: 'i', 0x0584: 'f', 0x0585: 'o', 0x0589: ':', 0x05c0: 'l',
+	0x05c3: ':', 0x05d5: 'l', 0x05d8: 'v', 0x05d9: '\'', 0x05df: 'l', 0x05e1: 'o',
+	0x05f3: '\'', 0x060d: ',', 0x0627: 'l', 0x0647: 'o', 0x0660: '.', 0x0661: 'l',
+	0x0665: 'o', 0x0667: 'V', 0x066b: ',', 0x066d: '*', 0x06be: 'o', 0x06c1: 'o',
+	0x06d4: '-', 0x06d5: 'o', 0x06f0: '.', 0x06f1: 'l', 0x06f5: 'o', 0x06f7: 'V',
+	0x0701: '.', 0x0702: '.', 0x0703: ':', 0x0704: ':', 0x07c0: 'O', 0x07ca: 'l',
+	0x07f4: '\'', 0x07f5: '\'', 0x07fa: '_', 0x0903: ':', 0x0966: 'o', 0x0969: '3',
+	0x097d: '?', 0x09e6: 'o', 0x09ea: '8', 0x09ed: '9', 0x0a66: 'o', 0x0a67: '9',
+	0x0a6a: '8', 0x0a83: ':', 0x0ae6: 'o', 0x0ae9: '3', 0x0b03: '8', 0x0b20: 'O',
+	0x0b66: 'o', 0x0b68: '9', 0x0be6: 'o', 0x0c02: 'o', 0x0c66: 'o', 0x0c82: 'o',
+	0x0ce6: 'O', 0x0d02: 'o', 0x0d1f: 's', 0x0d20: 'o', 0x0d66: 'o', 0x0d6d: '9',
+	0x0d82: 'o', 0x0e50: 'o', 0x0ed0: 'o', 0x1004: 'c', 0x101d: 'o', 0x1040: 'o',
+	0x105a: 'c', 0x10e7: 'y', 0x10ff: 'o', 0x1200: 'U', 0x12d0: 'O', 0x13a0: 'D',
+	0x13a1: 'R', 0x13a2: 'T', 0x13a5: 'i', 0x13a9: 'Y', 0x13aa: 'A', 0x13ab: 'J',
+	0x13ac: 'E', 0x13ae: '?', 0x13b3: 'W', 0x13b7: 'M', 0x13bb: 'H', 0x13bd: 'Y',
+	0x13c0: 'G', 0x13c2: 'h', 0x13c3: 'Z', 0x13ce: '4', 0x13cf: 'b', 0x13d2: 'R',
+	0x13d4: 'W', 0x13d5: 'S', 0x13d9: 'V', 0x13da: 'S', 0x13de: 'L', 0x13df: 'C',
+	0x13e2: 'P', 0x13e6: 'K', 0x13e7: 'd', 0x13ee: '6', 0x13f3: 'G', 0x13f4: 'B',
+	0x1400: '=', 0x142f: 'V', 0x1433: '>', 0x1438: '<', 0x144a: '\'', 0x144c: 'U',
+	0x146d: 'P', 0x146f: 'd', 0x1472: 'b', 0x148d: 'J', 0x14aa: 'L', 0x14bf: '2',
+	0x1541: 'x', 0x157c: 'H', 0x157d: 'x', 0x1587: 'R', 0x15af: 'b', 0x15b4: 'F',
+	0x15c5: 'A', 0x15de: 'D', 0x15ea: 'D', 0x15f0: 'M', 0x15f7: 'B', 0x166d: 'X',
+	0x166e: 'x', 0x16b2: '<', 0x16b7: 'X', 0x16c1: 'l', 0x16cc: '\'', 0x16d5: 'K',
+	0x16d6: 'M', 0x16ec: ':', 0x16ed: '+', 0x1735: '/', 0x17e0: 'o', 0x1803: ':',
+	0x1809: ':', 0x1d04: 'c', 0x1d0f: 'o', 0x1d11: 'o', 0x1d1c: 'u', 0x1d20: 'v',
+	0x1d21: 'w', 0x1d22: 'z', 0x1d26: 'r', 0x1d83: 'g', 0x1d8c: 'y', 0x1e9d: 'f',
+	0x1eff: 'y', 0x1fbd: '\'', 0x1fbe: 'i', 0x1fbf: '\'', 0x1fc0: '~', 0x1fef: '\'',
+	0x1ffd: '\'', 0x1ffe: '\'', 0x2010: '-', 0x2011: '-', 0x2012: '-', 0x2013: '-',
+	0x2018: '\'', 0x2019: '\'', 0x201a: ',', 0x201b: '\'', 0x2024: '.', 0x2032: '\'',
+	0x2035: '\'', 0x2039: '<', 0x203a: '>', 0x2041: '/', 0x2043: '-', 0x2044: '/',
+	0x204e: '*', 0x2053: '~', 0x205a: ':', 0x2102: 'C', 0x210a: 'g', 0x210b: 'H',
+	0x210c: 'H', 0x210d: 'H', 0x210e: 'h', 0x2110: 'l', 0x2111: 'l', 0x2112: 'L',
+	0x2113: 'l', 0x2115: 'N', 0x2119: 'P', 0x211a: 'Q', 0x211b: 'R', 0x211c: 'R',
+	0x211d: 'R', 0x2124: 'Z', 0x2128: 'Z', 0x212a: 'K', 0x212c: 'B', 0x212d: 'C',
+	0x212e: 'e', 0x212f: 'e', 0x2130: 'E', 0x2131: 'F', 0x2133: 'M', 0x2134: 'o',
+	0x2139: 'i', 0x213d: 'y', 0x2145: 'D', 0x2146: 'd', 0x2147: 'e', 0x2148: 'i',
+	0x2149: 'j', 0x2160: 'l', 0x2164: 'V', 0x2169: 'X', 0x216c: 'L', 0x216d: 'C',
+	0x216e: 'D', 0x216f: 'M', 0x2170: 'i', 0x2174: 'v', 0x2179: 'x', 0x217c: 'l',
+	0x217d: 'c', 0x217e: 'd', 0x2212: '-', 0x2215: '/', 0x2216: '\\', 0x2217: '*',
+	0x2223: 'l', 0x2228: 'v', 0x222a: 'U', 0x2236: ':', 0x223c: '~', 0x22a4: 'T',
+	0x22c1: 'v', 0x22c3: 'U', 0x22ff: 'E', 0x2373: 'i', 0x2374: 'p', 0x237a: 'a',
+	0x23fd: 'l', 0x2571: '/', 0x2573: 'X', 0x2768: '(', 0x2769: ')', 0x276e: '<',
+	0x276f: '>', 0x2772: '(', 0x2773: ')', 0x2774: '{', 0x2775: '}', 0x2795: '+',
+	0x2796: '-', 0x27cb: '/', 0x27cd: '\\', 0x27d9: 'T', 0x292b: 'x', 0x292c: 'x',
+	0x29f5: '\\', 0x29f8: '/', 0x29f9: '\\', 0x2a2f: 'x', 0x2c82: 'B', 0x2c85: 'r',
+	0x2c8e: 'H', 0x2c92: 'l', 0x2c93: 'i', 0x2c94: 'K', 0x2c98: 'M', 0x2c9a: 'N',
+	0x2c9c: '3', 0x2c9e: 'O', 0x2c9f: 'o', 0x2ca2: 'P', 0x2ca3: 'p', 0x2ca4: 'C',
+	0x2ca5: 'c', 0x2ca6: 'T', 0x2ca8: 'Y', 0x2ca9: 'y', 0x2cac: 'X', 0x2cba: '-',
+	0x2cbb: '-', 0x2cbd: 'w', 0x2cc4: '3', 0x2cc6: '/', 0x2cc7: '/', 0x2cca: '9',
+	0x2ccb: '9', 0x2ccc: '3', 0x2cce: 'P', 0x2ccf: 'p', 0x2cd0: 'L', 0x2cd2: '6',
+	0x2cd3: '6', 0x2cdc: '6', 0x2d38: 'V', 0x2d39: 'E', 0x2d4f: 'l', 0x2d51: '!',
+	0x2d54: 'O', 0x2d55: 'Q', 0x2d5d: 'X', 0x2e40: '=', 0x2f02: '\\', 0x2f03: '/',
+	0x3007: 'O', 0x3014: '(', 0x3015: ')', 0x3033: '/', 0x30a0: '=', 0x30ce: '/',
+	0x31d3: '/', 0x31d4: '\\', 0x4e36: '\\', 0x4e3f: '/', 0xa4d0: 'B', 0xa4d1: 'P',
+	0xa4d2: 'd', 0xa4d3: 'D', 0xa4d4: 'T', 0xa4d6: 'G', 0xa4d7: 'K', 0xa4d9: 'J',
+	0xa4da: 'C', 0xa4dc: 'Z', 0xa4dd: 'F', 0xa4df: 'M', 0xa4e0: 'N', 0xa4e1: 'L',
+	0xa4e2: 'S', 0xa4e3: 'R', 0xa4e6: 'V', 0xa4e7: 'H', 0xa4ea: 'W', 0xa4eb: 'X',
+	0xa4ec: 'Y', 0xa4ee: 'A', 0xa4f0: 'E', 0xa4f2: 'l', 0xa4f3: 'O', 0xa4f4: 'U',
+	0xa4f8: '.', 0xa4f9: ',', 0xa4fd: ':', 0xa4ff: '=', 0xa60e: '.', 0xa644: '2',
+	0xa647: 'i', 0xa6df: 'V', 0xa6eb: '?', 0xa6ef: '2', 0xa731: 's', 0xa75a: '2',
+	0xa76a: '3', 0xa76e: '9', 0xa778: '&', 0xa789: ':', 0xa78c: '\'', 0xa798: 'F',
+	0xa799: 'f', 0xa79f: 'u', 0xa7ab: '3', 0xa7b2: 'J', 0xa7b3: 'X', 0xa7b4: 'B',
+	0xab32: 'e', 0xab35: 'f', 0xab3d: 'o', 0xab47: 'r', 0xab48: 'r', 0xab4e: 'u',
+	0xab52: 'u', 0xab5a: 'y', 0xab75: 'i', 0xab81: 'r', 0xab83: 'w', 0xab93: 'z',
+	0xaba9: 'v', 0xabaa: 's', 0xabaf: 'c', 0xfba6: 'o', 0xfba7: 'o', 0xfba8: 'o',
+	0xfba9: 'o', 0xfbaa: 'o', 0xfbab: 'o', 0xfbac: 'o', 0xfbad: 'o', 0xfd3e: '(',
+	0xfd3f: ')', 0xfe30: ':', 0xfe4d: '_', 0xfe4e: '_', 0xfe4f: '_', 0xfe58: '-',
+	0xfe68: '\\', 0xfe8d: 'l', 0xfe8e: 'l', 0xfee9: 'o', 0xfeea: 'o', 0xfeeb: 'o',
+	0xfeec: 'o', 0xff01: '!', 0xff07: '\'', 0xff1a: ':', 0xff21: 'A', 0xff22: 'B',
+	0xff23: 'C', 0xff25: 'E', 0xff28: 'H', 0xff29: 'l', 0xff2a: 'J', 0xff2b: 'K',
+	0xff2d: 'M', 0xff2e: 'N', 0xff2f: 'O', 0xff30: 'P', 0xff33: 'S', 0xff34: 'T',
+	0xff38: 'X', 0xff39: 'Y', 0xff3a: 'Z', 0xff3b: '(', 0xff3c: '\\', 0xff3d: ')',
+	0xff40: '\'', 0xff41: 'a', 0xff43: 'c', 0xff45: 'e', 0xff47: 'g', 0xff48: 'h',
+	0xff49: 'i', 0xff4a: 'j', 0xff4c: 'l', 0xff4f: 'o', 0xff50: 'p', 0xff53: 's',
+	0xff56: 'v', 0xff58: 'x', 0xff59: 'y', 0xffe8: 'l', 0x10282: 'B', 0x10286: 'E',
+	0x10287: 'F', 0x1028a: 'l', 0x10290: 'X', 0x10292: 'O', 0x10295: 'P', 0x10296: 'S',
+	0x10297: 'T', 0x1029b: '+', 0x102a0: 'A', 0x102a1: 'B', 0x102a2: 'C', 0x102a5: 'F',
+	0x102ab: 'O', 0x102b0: 'M', 0x102b1: 'T', 0x102b2: 'Y', 0x102b4: 'X', 0x102cf: 'H',
+	0x102f5: 'Z', 0x10301: 'B', 0x10302: 'C', 0x10309: 'l', 0x10311: 'M', 0x10315: 'T',
+	0x10317: 'X', 0x1031a: '8', 0x1031f: '*', 0x10320: 'l', 0x10322: 'X', 0x10404: 'O',
+	0x10415: 'C', 0x1041b: 'L', 0x10420: 'S', 0x1042c: 'o', 0x1043d: 'c', 0x10448: 's',
+	0x104b4: 'R', 0x104c2: 'O', 0x104ce: 'U', 0x104d2: '7', 0x104ea: 'o', 0x104f6: 'u',
+	0x10513: 'N', 0x10516: 'O', 0x10518: 'K', 0x1051c: 'C', 0x1051d: 'V', 0x10525: 'F',
+	0x10526: 'L', 0x10527: 'X', 0x10a50: '.', 0x114d0: 'o', 0x11706: 'v', 0x1170a: 'w',
+	0x1170e: 'w', 0x1170f: 'w', 0x118a0: 'V', 0x118a2: 'F', 0x118a3: 'L', 0x118a4: 'Y',
+	0x118a6: 'E', 0x118a9: 'Z', 0x118ac: '9', 0x118ae: 'E', 0x118af: '4', 0x118b2: 'L',
+	0x118b5: 'O', 0x118b8: 'U', 0x118bb: '5', 0x118bc: 'T', 0x118c0: 'v', 0x118c1: 's',
+	0x118c2: 'F', 0x118c3: 'i', 0x118c4: 'z', 0x118c6: '7', 0x118c8: 'o', 0x118ca: '3',
+	0x118cc: '9', 0x118d5: '6', 0x118d6: '9', 0x118d7: 'o', 0x118d8: 'u', 0x118dc: 'y',
+	0x118e0: 'O', 0x118e5: 'Z', 0x118e6: 'W', 0x118e9: 'C', 0x118ec: 'X', 0x118ef: 'W',
+	0x118f2: 'C', 0x11dd9: ':', 0x11dda: 'l', 0x11de0: 'O', 0x11de1: 'l', 0x16eaa: 'l',
+	0x16eb6: 'b', 0x16f08: 'V', 0x16f0a: 'T', 0x16f16: 'L', 0x16f28: 'l', 0x16f35: 'R',
+	0x16f3a: 'S', 0x16f3b: '3', 0x16f3f: '>', 0x16f40: 'A', 0x16f42: 'U', 0x16f43: 'Y',
+	0x16f51: '\'', 0x16f52: '\'', 0x1ccd6: 'A', 0x1ccd7: 'B', 0x1ccd8: 'C', 0x1ccd9: 'D',
+	0x1ccda: 'E', 0x1ccdb: 'F', 0x1ccdc: 'G', 0x1ccdd: 'H', 0x1ccde: 'l', 0x1ccdf: 'J',
+	0x1cce0: 'K', 0x1cce1: 'L', 0x1cce2: 'M', 0x1cce3: 'N', 0x1cce4: 'O', 0x1cce5: 'P',
+	0x1cce6: 'Q', 0x1cce7: 'R', 0x1cce8: 'S', 0x1cce9: 'T', 0x1ccea: 'U', 0x1cceb: 'V',
+	0x1ccec: 'W', 0x1cced: 'X', 0x1ccee: 'Y', 0x1ccef: 'Z', 0x1ccf0: 'O', 0x1ccf1: 'l',
+	0x1ccf2: '2', 0x1ccf3: '3', 0x1ccf4: '4', 0x1ccf5: '5', 0x1ccf6: '6', 0x1ccf7: '7',
+	0x1ccf8: '8', 0x1ccf9: '9', 0x1d114: '{', 0x1d16d: '.', 0x1d206: '3', 0x1d20d: 'V',
+	0x1d20f: '\\', 0x1d212: '7', 0x1d213: 'F', 0x1d216: 'R', 0x1d22a: 'L', 0x1d236: '<',
+	0x1d237: '>', 0x1d23a: '/', 0x1d23b: '\\', 0x1d400: 'A', 0x1d401: 'B', 0x1d402: 'C',
+	0x1d403: 'D', 0x1d404: 'E', 0x1d405: 'F', 0x1d406: 'G', 0x1d407: 'H', 0x1d408: 'l',
+	0x1d409: 'J', 0x1d40a: 'K', 0x1d40b: 'L', 0x1d40c: 'M', 0x1d40d: 'N', 0x1d40e: 'O',
+	0x1d40f: 'P', 0x1d410: 'Q', 0x1d411: 'R', 0x1d412: 'S', 0x1d413: 'T', 0x1d414: 'U',
+	0x1d415: 'V', 0x1d416: 'W', 0x1d417: 'X', 0x1d418: 'Y', 0x1d419: 'Z', 0x1d41a: 'a',
+	0x1d41b: 'b', 0x1d41c: 'c', 0x1d41d: 'd', 0x1d41e: 'e', 0x1d41f: 'f', 0x1d420: 'g',
+	0x1d421: 'h', 0x1d422: 'i', 0x1d423: 'j', 0x1d424: 'k', 0x1d425: 'l', 0x1d427: 'n',
+	0x1d428: 'o', 0x1d429: 'p', 0x1d42a: 'q', 0x1d42b: 'r', 0x1d42c: 's', 0x1d42d: 't',
+	0x1d42e: 'u', 0x1d42f: 'v', 0x1d430: 'w', 0x1d431: 'x', 0x1d432: 'y', 0x1d433: 'z',
+	0x1d434: 'A', 0x1d435: 'B', 0x1d436: 'C', 0x1d437: 'D', 0x1d438: 'E', 0x1d439: 'F',
+	0x1d43a: 'G', 0x1d43b: 'H', 0x1d43c: 'l', 0x1d43d: 'J', 0x1d43e: 'K', 0x1d43f: 'L',
+	0x1d440: 'M', 0x1d441: 'N', 0x1d442: 'O', 0x1d443: 'P', 0x1d444: 'Q', 0x1d445: 'R',
+	0x1d446: 'S', 0x1d447: 'T', 0x1d448: 'U', 0x1d449: 'V', 0x1d44a: 'W', 0x1d44b: 'X',
+	0x1d44c: 'Y', 0x1d44d: 'Z', 0x1d44e: 'a', 0x1d44f: 'b', 0x1d450: 'c', 0x1d451: 'd',
+	0x1d452: 'e', 0x1d453: 'f', 0x1d454: 'g', 0x1d456: 'i', 0x1d457: 'j', 0x1d458: 'k',
+	0x1d459: 'l', 0x1d45b: 'n', 0x1d45c: 'o', 0x1d45d: 'p', 0x1d45e: 'q', 0x1d45f: 'r',
+	0x1d460: 's', 0x1d461: 't', 0x1d462: 'u', 0x1d463: 'v', 0x1d464: 'w', 0x1d465: 'x',
+	0x1d466: 'y', 0x1d467: 'z', 0x1d468: 'A', 0x1d469: 'B', 0x1d46a: 'C', 0x1d46b: 'D',
+	0x1d46c: 'E', 0x1d46d: 'F', 0x1d46e: 'G', 0x1d46f: 'H', 0x1d470: 'l', 0x1d471: 'J',
+	0x1d472: 'K', 0x1d473: 'L', 0x1d474: 'M', 0x1d475: 'N', 0x1d476: 'O', 0x1d477: 'P',
+	0x1d478: 'Q', 0x1d479: 'R', 0x1d47a: 'S', 0x1d47b: 'T', 0x1d47c: 'U', 0x1d47d: 'V',
+	0x1d47e: 'W', 0x1d47f: 'X', 0x1d480: 'Y', 0x1d481: 'Z', 0x1d482: 'a', 0x1d483: 'b',
+	0x1d484: 'c', 0x1d485: 'd', 0x1d486: 'e', 0x1d487: 'f', 0x1d488: 'g', 0x1d489: 'h',
+	0x1d48a: 'i', 0x1d48b: 'j', 0x1d48c: 'k', 0x1d48d: 'l', 0x1d48f: 'n', 0x1d490: 'o',
+	0x1d491: 'p', 0x1d492: 'q', 0x1d493: 'r', 0x1d494: 's', 0x1d495: 't', 0x1d496: 'u',
+	0x1d497: 'v', 0x1d498: 'w', 0x1d499: 'x', 0x1d49a: 'y', 0x1d49b: 'z', 0x1d49c: 'A',
+	0x1d49e: 'C', 0x1d49f: 'D', 0x1d4a2: 'G', 0x1d4a5: 'J', 0x1d4a6: 'K', 0x1d4a9: 'N',
+	0x1d4aa: 'O', 0x1d4ab: 'P', 0x1d4ac: 'Q', 0x1d4ae: 'S', 0x1d4af: 'T', 0x1d4b0: 'U',
+	0x1d4b1: 'V', 0x1d4b2: 'W', 0x1d4b3: 'X', 0x1d4b4: 'Y', 0x1d4b5: 'Z', 0x1d4b6: 'a',
+	0x1d4b7: 'b', 0x1d4b8: 'c', 0x1d4b9: 'd', 0x1d4bb: 'f', 0x1d4bd: 'h', 0x1d4be: 'i',
+	0x1d4bf: 'j', 0x1d4c0: 'k', 0x1d4c1: 'l', 0x1d4c3: 'n', 0x1d4c5: 'p', 0x1d4c6: 'q',
+	0x1d4c7: 'r', 0x1d4c8: 's', 0x1d4c9: 't', 0x1d4ca: 'u', 0x1d4cb: 'v', 0x1d4cc: 'w',
+	0x1d4cd: 'x', 0x1d4ce: 'y', 0x1d4cf: 'z', 0x1d4d0: 'A', 0x1d4d1: 'B', 0x1d4d2: 'C',
+	0x1d4d3: 'D', 0x1d4d4: 'E', 0x1d4d5: 'F', 0x1d4d6: 'G', 0x1d4d7: 'H', 0x1d4d8: 'l',
+	0x1d4d9: 'J', 0x1d4da: 'K', 0x1d4db: 'L', 0x1d4dc: 'M', 0x1d4dd: 'N', 0x1d4de: 'O',
+	0x1d4df: 'P', 0x1d4e0: 'Q', 0x1d4e1: 'R', 0x1d4e2: 'S', 0x1d4e3: 'T', 0x1d4e4: 'U',
+	0x1d4e5: 'V', 0x1d4e6: 'W', 0x1d4e7: 'X', 0x1d4e8: 'Y', 0x1d4e9: 'Z', 0x1d4ea: 'a',
+	0x1d4eb: 'b', 0x1d4ec: 'c', 0x1d4ed: 'd', 0x1d4ee: 'e', 0x1d4ef: 'f', 0x1d4f0: 'g',
+	0x1d4f1: 'h', 0x1d4f2: 'i', 0x1d4f3: 'j', 0x1d4f4: 'k', 0x1d4f5: 'l', 0x1d4f7: 'n',
+	0x1d4f8: 'o', 0x1d4f9: 'p', 0x1d4fa: 'q', 0x1d4fb: 'r', 0x1d4fc: 's', 0x1d4fd: 't',
+	0x1d4fe: 'u', 0x1d4ff: 'v', 0x1d500: 'w', 0x1d501: 'x', 0x1d502: 'y', 0x1d503: 'z',
+	0x1d504: 'A', 0x1d505: 'B', 0x1d507: 'D', 0x1d508: 'E', 0x1d509: 'F', 0x1d50a: 'G',
+	0x1d50d: 'J', 0x1d50e: 'K', 0x1d50f: 'L', 0x1d510: 'M', 0x1d511: 'N', 0x1d512: 'O',
+	0x1d513: 'P', 0x1d514: 'Q', 0x1d516: 'S', 0x1d517: 'T', 0x1d518: 'U', 0x1d519: 'V',
+	0x1d51a: 'W', 0x1d51b: 'X', 0x1d51c: 'Y', 0x1d51e: 'a', 0x1d51f: 'b', 0x1d520: 'c',
+	0x1d521: 'd', 0x1d522: 'e', 0x1d523: 'f', 0x1d524: 'g', 0x1d525: 'h', 0x1d526: 'i',
+	0x1d527: 'j', 0x1d528: 'k', 0x1d529: 'l', 0x1d52b: 'n', 0x1d52c: 'o', 0x1d52d: 'p',
+	0x1d52e: 'q', 0x1d52f: 'r', 0x1d530: 's', 0x1d531: 't', 0x1d532: 'u', 0x1d533: 'v',
+	0x1d534: 'w', 0x1d535: 'x', 0x1d536: 'y', 0x1d537: 'z', 0x1d538: 'A', 0x1d539: 'B',
+	0x1d53b: 'D', 0x1d53c: 'E', 0x1d53d: 'F', 0x1d53e: 'G', 0x1d540: 'l', 0x1d541: 'J',
+	0x1d542: 'K', 0x1d543: 'L', 0x1d544: 'M', 0x1d546: 'O', 0x1d54a: 'S', 0x1d54b: 'T',
+	0x1d54c: 'U', 0x1d54d: 'V', 0x1d54e: 'W', 0x1d54f: 'X', 0x1d550: 'Y', 0x1d552: 'a',
+	0x1d553: 'b', 0x1d554: 'c', 0x1d555: 'd', 0x1d556: 'e', 0x1d557: 'f', 0x1d558: 'g',
+	0x1d559: 'h', 0x1d55a: 'i', 0x1d55b: 'j', 0x1d55c: 'k', 0x1d55d: 'l', 0x1d55f: 'n',
+	0x1d560: 'o', 0x1d561: 'p', 0x1d562: 'q', 0x1d563: 'r', 0x1d564: 's', 0x1d565: 't',
+	0x1d566: 'u', 0x1d567: 'v', 0x1d568: 'w', 0x1d569: 'x', 0x1d56a: 'y', 0x1d56b: 'z',
+	0x1d56c: 'A', 0x1d56d: 'B', 0x1d56e: 'C', 0x1d56f: 'D', 0x1d570: 'E', 0x1d571: 'F',
+	0x1d572: 'G', 0x1d573: 'H', 0x1d574: 'l', 0x1d575: 'J', 0x1d576: 'K', 0x1d577: 'L',
+	0x1d578: 'M', 0x1d579: 'N', 0x1d57a: 'O', 0x1d57b: 'P', 0x1d57c: 'Q', 0x1d57d: 'R',
+	0x1d57e: 'S', 0x1d57f: 'T', 0x1d580: 'U', 0x1d581: 'V', 0x1d582: 'W', 0x1d583: 'X',
+	0x1d584: 'Y', 0x1d585: 'Z', 0x1d586: 'a', 0x1d587: 'b', 0x1d588: 'c', 0x1d589: 'd',
+	0x1d58a: 'e', 0x1d58b: 'f', 0x1d58c: 'g', 0x1d58d: 'h', 0x1d58e: 'i', 0x1d58f: 'j',
+	0x1d590: 'k', 0x1d591: 'l', 0x1d593: 'n', 0x1d594: 'o', 0x1d595: 'p', 0x1d596: 'q',
+	0x1d597: 'r', 0x1d598: 's', 0x1d599: 't', 0x1d59a: 'u', 0x1d59b: 'v', 0x1d59c: 'w',
+	0x1d59d: 'x', 0x1d59e: 'y', 0x1d59f: 'z', 0x1d5a0: 'A', 0x1d5a1: 'B', 0x1d5a2: 'C',
+	0x1d5a3: 'D', 0x1d5a4: 'E', 0x1d5a5: 'F', 0x1d5a6: 'G', 0x1d5a7: 'H', 0x1d5a8: 'l',
+	0x1d5a9: 'J', 0x1d5aa: 'K', 0x1d5ab: 'L', 0x1d5ac: 'M', 0x1d5ad: 'N', 0x1d5ae: 'O',
+	0x1d5af: 'P', 0x1d5b0: 'Q', 0x1d5b1: 'R', 0x1d5b2: 'S', 0x1d5b3: 'T', 0x1d5b4: 'U',
+	0x1d5b5: 'V', 0x1d5b6: 'W', 0x1d5b7: 'X', 0x1d5b8: 'Y', 0x1d5b9: 'Z', 0x1d5ba: 'a',
+	0x1d5bb: 'b', 0x1d5bc: 'c', 0x1d5bd: 'd', 0x1d5be: 'e', 0x1d5bf: 'f', 0x1d5c0: 'g',
+	0x1d5c1: 'h', 0x1d5c2: 'i', 0x1d5c3: 'j', 0x1d5c4: 'k', 0x1d5c5: 'l', 0x1d5c7: 'n',
+	0x1d5c8: 'o', 0x1d5c9: 'p', 0x1d5ca: 'q', 0x1d5cb: 'r', 0x1d5cc: 's', 0x1d5cd: 't',
+	0x1d5ce: 'u', 0x1d5cf: 'v', 0x1d5d0: 'w', 0x1d5d1: 'x', 0x1d5d2: 'y', 0x1d5d3: 'z',
+	0x1d5d4: 'A', 0x1d5d5: 'B', 0x1d5d6: 'C', 0x1d5d7: 'D', 0x1d5d8: 'E', 0x1d5d9: 'F',
+	0x1d5da: 'G', 0x1d5db: 'H', 0x1d5dc: 'l', 0x1d5dd: 'J', 0x1d5de: 'K', 0x1d5df: 'L',
+	0x1d5e0: 'M', 0x1d5e1: 'N', 0x1d5e2: 'O', 0x1d5e3: 'P', 0x1d5e4: 'Q', 0x1d5e5: 'R',
+	0x1d5e6: 'S', 0x1d5e7: 'T', 0x1d5e8: 'U', 0x1d5e9: 'V', 0x1d5ea: 'W', 0x1d5eb: 'X',
+	0x1d5ec: 'Y', 0x1d5ed: 'Z', 0x1d5ee: 'a', 0x1d5ef: 'b', 0x1d5f0: 'c', 0x1d5f1: 'd',
+	0x1d5f2: 'e', 0x1d5f3: 'f', 0x1d5f4: 'g', 0x1d5f5: 'h', 0x1d5f6: 'i', 0x1d5f7: 'j',
+	0x1d5f8: 'k', 0x1d5f9: 'l', 0x1d5fb: 'n', 0x1d5fc: 'o', 0x1d5fd: 'p', 0x1d5fe: 'q',
+	0x1d5ff: 'r', 0x1d600: 's', 0x1d601: 't', 0x1d602: 'u', 0x1d603: 'v', 0x1d604: 'w',
+	0x1d605: 'x', 0x1d606: 'y', 0x1d607: 'z', 0x1d608: 'A', 0x1d609: 'B', 0x1d60a: 'C',
+	0x1d60b: 'D', 0x1d60c: 'E', 0x1d60d: 'F', 0x1d60e: 'G', 0x1d60f: 'H', 0x1d610: 'l',
+	0x1d611: 'J', 0x1d612: 'K', 0x1d613: 'L', 0x1d614: 'M', 0x1d615: 'N', 0x1d616: 'O',
+	0x1d617: 'P', 0x1d618: 'Q', 0x1d619: 'R', 0x1d61a: 'S', 0x1d61b: 'T', 0x1d61c: 'U',
+	0x1d61d: 'V', 0x1d61e: 'W', 0x1d61f: 'X', 0x1d620: 'Y', 0x1d621: 'Z', 0x1d622: 'a',
+	0x1d623: 'b', 0x1d624: 'c', 0x1d625: 'd', 0x1d626: 'e', 0x1d627: 'f', 0x1d628: 'g',
+	0x1d629: 'h', 0x1d62a: 'i', 0x1d62b: 'j', 0x1d62c: 'k', 0x1d62d: 'l', 0x1d62f: 'n',
+	0x1d630: 'o', 0x1d631: 'p', 0x1d632: 'q', 0x1d633: 'r', 0x1d634: 's', 0x1d635: 't',
+	0x1d636: 'u', 0x1d637: 'v', 0x1d638: 'w', 0x1d639: 'x', 0x1d63a: 'y', 0x1d63b: 'z',
+	0x1d63c: 'A', 0x1d63d: 'B', 0x1d63e: 'C', 0x1d63f: 'D', 0x1d640: 'E', 0x1d641: 'F',
+	0x1d642: 'G', 0x1d643: 'H', 0x1d644: 'l', 0x1d645: 'J', 0x1d646: 'K', 0x1d647: 'L',
+	0x1d648: 'M', 0x1d649: 'N', 0x1d64a: 'O', 0x1d64b: 'P', 0x1d64c: 'Q', 0x1d64d: 'R',
+	0x1d64e: 'S', 0x1d64f: 'T', 0x1d650: 'U', 0x1d651: 'V', 0x1d652: 'W', 0x1d653: 'X',
+	0x1d654: 'Y', 0x1d655: 'Z', 0x1d656: 'a', 0x1d657: 'b', 0x1d658: 'c', 0x1d659: 'd',
+	0x1d65a: 'e', 0x1d65b: 'f', 0x1d65c: 'g', 0x1d65d: 'h', 0x1d65e: 'i', 0x1d65f: 'j',
+	0x1d660: 'k', 0x1d661: 'l', 0x1d663: 'n', 0x1d664: 'o', 0x1d665: 'p', 0x1d666: 'q',
+	0x1d667: 'r', 0x1d668: 's', 0x1d669: 't', 0x1d66a: 'u', 0x1d66b: 'v', 0x1d66c: 'w',
+	0x1d66d: 'x', 0x1d66e: 'y', 0x1d66f: 'z', 0x1d670: 'A', 0x1d671: 'B', 0x1d672: 'C',
+	0x1d673: 'D', 0x1d674: 'E', 0x1d675: 'F', 0x1d676: 'G', 0x1d677: 'H', 0x1d678: 'l',
+	0x1d679: 'J', 0x1d67a: 'K', 0x1d67b: 'L', 0x1d67c: 'M', 0x1d67d: 'N', 0x1d67e: 'O',
+	0x1d67f: 'P', 0x1d680: 'Q', 0x1d681: 'R', 0x1d682: 'S', 0x1d683: 'T', 0x1d684: 'U',
+	0x1d685: 'V', 0x1d686: 'W', 0x1d687: 'X', 0x1d688: 'Y', 0x1d689: 'Z', 0x1d68a: 'a',
+	0x1d68b: 'b', 0x1d68c: 'c', 0x1d68d: 'd', 0x1d68e: 'e', 0x1d68f: 'f', 0x1d690: 'g',
+	0x1d691: 'h', 0x1d692: 'i', 0x1d693: 'j', 0x1d694: 'k', 0x1d695: 'l', 0x1d697: 'n',
+	0x1d698: 'o', 0x1d699: 'p', 0x1d69a: 'q', 0x1d69b: 'r', 0x1d69c: 's', 0x1d69d: 't',
+	0x1d69e: 'u', 0x1d69f: 'v', 0x1d6a0: 'w', 0x1d6a1: 'x', 0x1d6a2: 'y', 0x1d6a3: 'z',
+	0x1d6a4: 'i', 0x1d6a8: 'A', 0x1d6a9: 'B', 0x1d6ac: 'E', 0x1d6ad: 'Z', 0x1d6ae: 'H',
+	0x1d6b0: 'l', 0x1d6b1: 'K', 0x1d6b3: 'M', 0x1d6b4: 'N', 0x1d6b6: 'O', 0x1d6b8: 'P',
+	0x1d6bb: 'T', 0x1d6bc: 'Y', 0x1d6be: 'X', 0x1d6c2: 'a', 0x1d6c4: 'y', 0x1d6ca: 'i',
+	0x1d6ce: 'v', 0x1d6d0: 'o', 0x1d6d2: 'p', 0x1d6d4: 'o', 0x1d6d6: 'u', 0x1d6e0: 'p',
+	0x1d6e2: 'A', 0x1d6e3: 'B', 0x1d6e6: 'E', 0x1d6e7: 'Z', 0x1d6e8: 'H', 0x1d6ea: 'l',
+	0x1d6eb: 'K', 0x1d6ed: 'M', 0x1d6ee: 'N', 0x1d6f0: 'O', 0x1d6f2: 'P', 0x1d6f5: 'T',
+	0x1d6f6: 'Y', 0x1d6f8: 'X', 0x1d6fc: 'a', 0x1d6fe: 'y', 0x1d704: 'i', 0x1d708: 'v',
+	0x1d70a: 'o', 0x1d70c: 'p', 0x1d70e: 'o', 0x1d710: 'u', 0x1d71a: 'p', 0x1d71c: 'A',
+	0x1d71d: 'B', 0x1d720: 'E', 0x1d721: 'Z', 0x1d722: 'H', 0x1d724: 'l', 0x1d725: 'K',
+	0x1d727: 'M', 0x1d728: 'N', 0x1d72a: 'O', 0x1d72c: 'P', 0x1d72f: 'T', 0x1d730: 'Y',
+	0x1d732: 'X', 0x1d736: 'a', 0x1d738: 'y', 0x1d73e: 'i', 0x1d742: 'v', 0x1d744: 'o',
+	0x1d746: 'p', 0x1d748: 'o', 0x1d74a: 'u', 0x1d754: 'p', 0x1d756: 'A', 0x1d757: 'B',
+	0x1d75a: 'E', 0x1d75b: 'Z', 0x1d75c: 'H', 0x1d75e: 'l', 0x1d75f: 'K', 0x1d761: 'M',
+	0x1d762: 'N', 0x1d764: 'O', 0x1d766: 'P', 0x1d769: 'T', 0x1d76a: 'Y', 0x1d76c: 'X',
+	0x1d770: 'a', 0x1d772: 'y', 0x1d778: 'i', 0x1d77c: 'v', 0x1d77e: 'o', 0x1d780: 'p',
+	0x1d782: 'o', 0x1d784: 'u', 0x1d78e: 'p', 0x1d790: 'A', 0x1d791: 'B', 0x1d794: 'E',
+	0x1d795: 'Z', 0x1d796: 'H', 0x1d798: 'l', 0x1d799: 'K', 0x1d79b: 'M', 0x1d79c: 'N',
+	0x1d79e: 'O', 0x1d7a0: 'P', 0x1d7a3: 'T', 0x1d7a4: 'Y', 0x1d7a6: 'X', 0x1d7aa: 'a',
+	0x1d7ac: 'y', 0x1d7b2: 'i', 0x1d7b6: 'v', 0x1d7b8: 'o', 0x1d7ba: 'p', 0x1d7bc: 'o',
+	0x1d7be: 'u', 0x1d7c8: 'p', 0x1d7ca: 'F', 0x1d7ce: 'O', 0x1d7cf: 'l', 0x1d7d0: '2',
+	0x1d7d1: '3', 0x1d7d2: '4', 0x1d7d3: '5', 0x1d7d4: '6', 0x1d7d5: '7', 0x1d7d6: '8',
+	0x1d7d7: '9', 0x1d7d8: 'O', 0x1d7d9: 'l', 0x1d7da: '2', 0x1d7db: '3', 0x1d7dc: '4',
+	0x1d7dd: '5', 0x1d7de: '6', 0x1d7df: '7', 0x1d7e0: '8', 0x1d7e1: '9', 0x1d7e2: 'O',
+	0x1d7e3: 'l', 0x1d7e4: '2', 0x1d7e5: '3', 0x1d7e6: '4', 0x1d7e7: '5', 0x1d7e8: '6',
+	0x1d7e9: '7', 0x1d7ea: '8', 0x1d7eb: '9', 0x1d7ec: 'O', 0x1d7ed: 'l', 0x1d7ee: '2',
+	0x1d7ef: '3', 0x1d7f0: '4', 0x1d7f1: '5', 0x1d7f2: '6', 0x1d7f3: '7', 0x1d7f4: '8',
+	0x1d7f5: '9', 0x1d7f6: 'O', 0x1d7f7: 'l', 0x1d7f8: '2', 0x1d7f9: '3', 0x1d7fa: '4',
+	0x1d7fb: '5', 0x1d7fc: '6', 0x1d7fd: '7', 0x1d7fe: '8', 0x1d7ff: '9', 0x1e6e9: '+',
+	0x1e8c7: 'l', 0x1e8cb: '8', 0x1ee00: 'l', 0x1ee24: 'o', 0x1ee64: 'o', 0x1ee80: 'l',
+	0x1ee84: 'o', 0x1f74c: 'C', 0x1f768: 'T', 0x1fbf0: 'O', 0x1fbf1: 'l', 0x1fbf2: '2',
+	0x1fbf3: '3', 0x1fbf4: '4', 0x1fbf5: '5', 0x1fbf6: '6', 0x1fbf7: '7', 0x1fbf8: '8',
+	0x1fbf9: '9',
+}
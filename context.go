@@ -0,0 +1,306 @@
+package chars
+
+/*
+context.go
+
+Keep the lines surrounding each location for --context and render them with every
+invisible character made visible, similar to cat -A
+*/
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// only this many bytes of each context line are kept, so that minified files do not use a lot of memory
+const maxContextLineBytes int = 512
+
+// ANSI escape sequences used when stdout is a terminal
+const (
+	ansiHighlight string = "\x1b[1;31m"
+	ansiVisible   string = "\x1b[2m"
+	ansiReset     string = "\x1b[0m"
+)
+
+// ContextLine - a line surrounding a location; Marked lines contain at least one location
+type ContextLine struct {
+	Line      uint64   `json:"line"`
+	Text      string   `json:"text"`
+	Marked    bool     `json:"marked"`
+	raw       []byte   // the line as it was read, including its line ending
+	marks     []uint64 // columns of the locations on this line
+	truncated bool
+}
+
+// contextTracker - a ring of the previous lines is kept until a location is found; it always
+// holds at least one line since a lone CR is only located once the following line has started
+type contextTracker struct {
+	around    int
+	cur       ContextLine
+	previous  []ContextLine
+	afterLeft int
+	kept      []ContextLine
+	holdFrom  uint64 // when not 0, lines starting with this one stay in the ring until released
+}
+
+func newContextTracker(around int) *contextTracker {
+	return &contextTracker{around: around, cur: ContextLine{Line: 1}}
+}
+
+// add - append byte b to the current line
+func (ctx *contextTracker) add(b byte) {
+	if len(ctx.cur.raw) >= maxContextLineBytes {
+		ctx.cur.truncated = true
+		return
+	}
+	ctx.cur.raw = append(ctx.cur.raw, b)
+}
+
+// addRun - append the bytes of run, none of which end the line
+func (ctx *contextTracker) addRun(run []byte) {
+	room := maxContextLineBytes - len(ctx.cur.raw)
+	if len(run) > room {
+		ctx.cur.truncated = true
+		run = run[:room]
+	}
+	ctx.cur.raw = append(ctx.cur.raw, run...)
+}
+
+// endLine - the current line is complete; keep it when it is marked or follows a marked line
+func (ctx *contextTracker) endLine() {
+	line := ctx.cur
+	ctx.cur = ContextLine{Line: line.Line + 1}
+	if line.Marked {
+		ctx.keepPrevious(len(ctx.previous)-ctx.around, len(ctx.previous))
+		ctx.kept = append(ctx.kept, line)
+		ctx.afterLeft = ctx.around
+		return
+	}
+	if ctx.afterLeft > 0 {
+		ctx.kept = append(ctx.kept, line)
+		ctx.afterLeft--
+		return
+	}
+	if len(ctx.previous) > ctx.around && (ctx.holdFrom == 0 || ctx.previous[0].Line < ctx.holdFrom) {
+		copy(ctx.previous, ctx.previous[1:])
+		ctx.previous = ctx.previous[:len(ctx.previous)-1]
+	}
+	ctx.previous = append(ctx.previous, line)
+}
+
+// hold - keep the lines starting with line in the ring, since a location may be recorded for them once
+// the end of the input is reached, such as trailing blank lines; 0 releases them
+func (ctx *contextTracker) hold(line uint64) {
+	ctx.holdFrom = line
+	if line == 0 && len(ctx.previous) > ctx.around+1 {
+		ctx.previous = append(ctx.previous[:0], ctx.previous[len(ctx.previous)-ctx.around-1:]...)
+	}
+}
+
+// keepPrevious - move the previous lines from index from up to index to into the kept lines; the lines
+// after to and those which are held stay in the ring
+func (ctx *contextTracker) keepPrevious(from, to int) {
+	from, to = max(0, from), min(len(ctx.previous), to)
+	ctx.kept = append(ctx.kept, ctx.previous[from:to]...)
+	rest := ctx.previous[:0]
+	for i, line := range ctx.previous {
+		if i >= to || i < from && ctx.holdFrom != 0 && line.Line >= ctx.holdFrom {
+			rest = append(rest, line)
+		}
+	}
+	ctx.previous = rest
+}
+
+// mark - a location was recorded; a lone CR is only known once the following line has started
+func (ctx *contextTracker) mark(at Location) {
+	if at.Line == ctx.cur.Line {
+		ctx.cur.Marked = true
+		ctx.cur.marks = append(ctx.cur.marks, at.Column)
+		return
+	}
+	for i := len(ctx.kept) - 1; i >= 0 && ctx.kept[i].Line >= at.Line; i-- {
+		if ctx.kept[i].Line == at.Line {
+			ctx.kept[i].Marked = true
+			ctx.kept[i].marks = append(ctx.kept[i].marks, at.Column)
+			return
+		}
+	}
+	for i := range ctx.previous {
+		if ctx.previous[i].Line != at.Line {
+			continue
+		}
+		ctx.previous[i].Marked = true
+		ctx.previous[i].marks = append(ctx.previous[i].marks, at.Column)
+		ctx.afterLeft = max(0, ctx.around-(len(ctx.previous)-1-i))
+		ctx.keepPrevious(i-ctx.around, i+ctx.around+1)
+		return
+	}
+}
+
+// unmark - a location passed to mark was discarded
+func (ctx *contextTracker) unmark(at Location) {
+	line := &ctx.cur
+	for i := range ctx.kept {
+		if ctx.kept[i].Line == at.Line {
+			line = &ctx.kept[i]
+		}
+	}
+	for i := range ctx.previous {
+		if ctx.previous[i].Line == at.Line {
+			line = &ctx.previous[i]
+		}
+	}
+	if i := slices.Index(line.marks, at.Column); line.Line == at.Line && i >= 0 {
+		line.marks = slices.Delete(line.marks, i, i+1)
+		line.Marked = len(line.marks) > 0
+	}
+}
+
+// dropUnmarked - remove the kept lines which are no longer within around lines of a marked line
+func (ctx *contextTracker) dropUnmarked() {
+	near := make([]bool, len(ctx.kept))
+	var marked uint64
+	found := false
+	for i, line := range ctx.kept {
+		if line.Marked {
+			marked, found = line.Line, true
+		}
+		near[i] = found && line.Line-marked <= uint64(ctx.around)
+	}
+	found = false
+	for i := len(ctx.kept) - 1; i >= 0; i-- {
+		if ctx.kept[i].Marked {
+			marked, found = ctx.kept[i].Line, true
+		}
+		near[i] = near[i] || found && marked-ctx.kept[i].Line <= uint64(ctx.around)
+	}
+	kept := ctx.kept[:0]
+	for i, line := range ctx.kept {
+		if near[i] {
+			kept = append(kept, line)
+		}
+	}
+	ctx.kept = kept
+}
+
+// finish - return the kept lines in order, rendered without color for JSON output; held lines can be
+// kept after the lines which follow them
+func (ctx *contextTracker) finish() []ContextLine {
+	if len(ctx.cur.raw) > 0 {
+		ctx.endLine()
+	}
+	sort.SliceStable(ctx.kept, func(i, j int) bool { return ctx.kept[i].Line < ctx.kept[j].Line })
+	ctx.dropUnmarked()
+	for i := range ctx.kept {
+		ctx.kept[i].Text = renderVisible(ctx.kept[i], false)
+	}
+	return ctx.kept
+}
+
+// renderVisible - replace invisible characters with a visible symbol, highlighting the marked columns
+func renderVisible(line ContextLine, color bool) string {
+	var sb strings.Builder
+	var column uint64
+	raw := line.raw
+	for i := 0; i < len(raw); {
+		r, size := utf8.DecodeRune(raw[i:])
+		for _, b := range raw[i : i+size] {
+			if b < 0x80 || b >= 0xc0 {
+				column++
+			}
+		}
+
+		var visible string
+		switch {
+		case r == utf8.RuneError && size == 1:
+			visible = fmt.Sprintf("\\x%02X", raw[i])
+		case r == '\r':
+			visible = "␍"
+		case r == '\n':
+			visible = "␊"
+		case r == '\t':
+			visible = "→"
+		case r < ' ':
+			visible = string(rune(0x2400 + r)) // Unicode control pictures, such as ␀
+		case r == 0x7f:
+			visible = "␡"
+		case r >= 0x80 && (unicode.IsControl(r) || unicode.Is(unicode.Cf, r) || unicode.IsSpace(r)):
+			visible = fmt.Sprintf("<U+%04X>", r)
+		}
+		i += size
+
+		marked := false
+		for _, m := range line.marks {
+			if m == column {
+				marked = true
+				break
+			}
+		}
+		switch {
+		case visible == "" && !marked:
+			sb.WriteRune(r)
+		case visible == "":
+			visible = string(r)
+			fallthrough
+		case !color:
+			sb.WriteString(visible)
+		case marked:
+			sb.WriteString(ansiHighlight + visible + ansiReset)
+		default:
+			sb.WriteString(ansiVisible + visible + ansiReset)
+		}
+	}
+	if line.truncated {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
+
+// isTerminal - color is only used when writing directly to a terminal and NO_COLOR is not set
+func isTerminal(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// outputContext - display the locations within each group of context lines followed by the lines themselves
+func outputContext(w io.Writer, s SpecialChars, color bool) {
+	width := len(fmt.Sprint(s.Context[len(s.Context)-1].Line))
+	next := 0
+	for start := 0; start < len(s.Context); {
+		end := start + 1
+		for end < len(s.Context) && s.Context[end].Line == s.Context[end-1].Line+1 {
+			end++
+		}
+		if start > 0 {
+			_, _ = fmt.Fprintln(w, "--")
+		}
+		for ; next < len(s.Locations) && s.Locations[next].Line <= s.Context[end-1].Line; next++ {
+			at := s.Locations[next]
+			_, _ = fmt.Fprintf(w, "%s:%d:%d: %s\n", s.Filename, at.Line, at.Column, at.Class)
+		}
+		for _, line := range s.Context[start:end] {
+			gutter := " "
+			if line.Marked {
+				gutter = ">"
+			}
+			_, _ = fmt.Fprintf(w, "%s %*d | %s\n", gutter, width, line.Line, renderVisible(line, color))
+		}
+		start = end
+	}
+	for ; next < len(s.Locations); next++ {
+		at := s.Locations[next]
+		_, _ = fmt.Fprintf(w, "%s:%d:%d: %s\n", s.Filename, at.Line, at.Column, at.Class)
+	}
+}
@@ -0,0 +1,130 @@
+package chars
+
+/*
+controls.go
+
+Count the control characters examined by searchForSpecialChars other than NUL, tab and line
+endings: form feed, vertical tab, backspace, escape, DEL and the rest of C0, along with the
+ANSI escape sequences found in log files and terminal captures, such as ESC [ 3 1 m.
+C1 controls, U+0080 to U+009F, are counted by runeTracker since they are UTF-8 characters.
+*/
+
+// states of the ANSI escape sequence being examined
+const (
+	seqNone      int = iota
+	seqEscape        // ESC was found
+	seqCsi           // ESC [ was found, a Control Sequence Introducer
+	seqOsc           // ESC ] was found, an Operating System Command ended by BEL or ESC \
+	seqOscEscape     // ESC was found within an Operating System Command
+)
+
+// controlTracker - counts control characters and escape sequences; a sequence can span more than one read
+type controlTracker struct {
+	formFeed    uint64
+	verticalTab uint64
+	backspace   uint64
+	escape      uint64
+	del         uint64
+	otherC0     uint64
+	ansi        uint64
+
+	sequence int
+	seqStart Location // where the ESC starting the sequence was found
+	escAt    Location // where an ESC within an Operating System Command was found
+}
+
+// isOtherC0 - return true for a C0 control character which is not counted on its own
+func isOtherC0(b byte) bool {
+	switch b {
+	case 0, '\t', '\n', '\r', '\f', '\v', '\b', 0x1b:
+		return false
+	}
+	return b < ' '
+}
+
+// add - examine byte b at position at; only called for control characters and bytes within an escape sequence
+func (ct *controlTracker) add(loc *locator, b byte, at Location) {
+	switch {
+	case b == '\f':
+		ct.formFeed++
+		loc.record("ff", at)
+	case b == '\v':
+		ct.verticalTab++
+		loc.record("vt", at)
+	case b == '\b':
+		ct.backspace++
+		loc.record("bs", at)
+	case b == 0x1b:
+		ct.escape++
+		loc.record("esc", at)
+	case b == 0x7f:
+		ct.del++
+		loc.record("del", at)
+	case isOtherC0(b):
+		ct.otherC0++
+		loc.record("c0", at)
+	}
+	ct.sequenceByte(loc, b, at)
+}
+
+// sequenceByte - advance the escape sequence state; a sequence which is cut short is not counted, and
+// ESC always starts a new sequence unless it may be the ESC \ ending an Operating System Command
+func (ct *controlTracker) sequenceByte(loc *locator, b byte, at Location) {
+	if b == 0x1b {
+		if ct.sequence == seqOsc {
+			ct.sequence, ct.escAt = seqOscEscape, at
+		} else {
+			ct.sequence, ct.seqStart = seqEscape, at
+		}
+		return
+	}
+
+	switch ct.sequence {
+	case seqEscape:
+		switch {
+		case b == '[':
+			ct.sequence = seqCsi
+		case b == ']':
+			ct.sequence = seqOsc
+		case b >= 0x40 && b <= 0x5f:
+			ct.found(loc) // a two character sequence, such as ESC M
+		default:
+			ct.sequence = seqNone
+		}
+	case seqCsi:
+		switch {
+		case b >= 0x40 && b <= 0x7e:
+			ct.found(loc)
+		case b >= 0x20 && b <= 0x3f:
+			// parameter and intermediate bytes
+		default:
+			ct.sequence = seqNone
+		}
+	case seqOsc:
+		if b == 0x07 {
+			ct.found(loc)
+		} else if b < ' ' {
+			ct.sequence = seqNone // such as a newline, when the terminal title is never ended
+		}
+	case seqOscEscape:
+		if b == '\\' {
+			ct.found(loc)
+			return
+		}
+		// the Operating System Command was never ended; its ESC starts a new sequence
+		ct.sequence, ct.seqStart = seqEscape, ct.escAt
+		ct.sequenceByte(loc, b, at)
+	}
+}
+
+// found - a complete escape sequence was examined
+func (ct *controlTracker) found(loc *locator) {
+	ct.ansi++
+	ct.sequence = seqNone
+	loc.record("ansi", ct.seqStart)
+}
+
+// inSequence - return true when an escape sequence has been started
+func (ct *controlTracker) inSequence() bool {
+	return ct.sequence != seqNone
+}
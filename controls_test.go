@@ -0,0 +1,70 @@
+package chars
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestAnsiSequences(t *testing.T) {
+	tests := []struct {
+		input   string
+		ansi    uint64
+		columns []uint64 // where each sequence starts, all on the first line
+	}{
+		{"\x1b[31mred\x1b[0m\n", 2, []uint64{1, 9}},
+		{"\x1b]0;title\x07 \x1b]0;title\x1b\\\n", 2, []uint64{1, 12}},
+		{"\x1bM up\n", 1, []uint64{1}},
+		// a second ESC starts over
+		{"a\x1b\x1b[31mb\n", 1, []uint64{3}},
+		// an Operating System Command which is never ended does not hide the sequences after it
+		{"x\x1b]0;title \x1b[31mred\x1b[0m\n", 2, []uint64{12, 20}},
+		{"\x1b]0;t\x1b[1m\n", 1, []uint64{6}},
+		{"\x1b[31\n", 0, nil},
+	}
+	opts := Options{Locations: true, LocationClasses: []string{"ansi"}}
+	for _, tt := range tests {
+		stats, cerr := searchForSpecialChars("ansi.txt", bufio.NewReader(strings.NewReader(tt.input)), opts)
+		if cerr.code != 0 {
+			t.Fatal(cerr.err)
+		}
+		var columns []uint64
+		for _, at := range stats.Locations {
+			columns = append(columns, at.Column)
+		}
+		if stats.AnsiSequences != tt.ansi || len(columns) != len(tt.columns) {
+			t.Errorf("%q: found %d sequences at %v, want %d at %v", tt.input, stats.AnsiSequences, columns, tt.ansi, tt.columns)
+			continue
+		}
+		for i := range columns {
+			if columns[i] != tt.columns[i] {
+				t.Errorf("%q: found sequences at %v, want %v", tt.input, columns, tt.columns)
+				break
+			}
+		}
+	}
+
+	// an Operating System Command ends at the end of its line
+	input := "x\x1b]0;title\nmore \x1b[31mred\x1b[0m\n"
+	stats, _ := searchForSpecialChars("ansi.txt", bufio.NewReader(strings.NewReader(input)), opts)
+	if stats.AnsiSequences != 2 {
+		t.Errorf("%q: found %d sequences, want 2", input, stats.AnsiSequences)
+	}
+}
+
+// TestControlsLocations - -f controls locates each of the control characters it fails on
+func TestControlsLocations(t *testing.T) {
+	input := "a\x0cb\x08c\x7f\n\u0085\x1b\x01\v\n"
+	opts := Options{Locations: true, LocationClasses: []string{"controls"}}
+	stats, cerr := searchForSpecialChars("ctl.txt", bufio.NewReader(strings.NewReader(input)), opts)
+	if cerr.code != 0 {
+		t.Fatal(cerr.err)
+	}
+	var classes []string
+	for _, at := range stats.Locations {
+		classes = append(classes, at.Class)
+	}
+	if got, want := strings.Join(classes, ","), "ff,bs,del,c1,esc,c0,vt"; got != want {
+		t.Errorf("located %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,80 @@
+package chars
+
+/*
+decode.go
+
+Transcode UTF-16 and UTF-32 input to UTF-8 before it is scanned so that the counts
+reflect characters instead of the individual bytes of each code unit
+*/
+
+import (
+	"io"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/encoding/unicode/utf32"
+)
+
+// UTF-16 without a BOM is only assumed when at least this many code units can be examined
+const utf16SniffMinUnits int = 8
+
+// countingReader - keep track of the number of raw bytes consumed by a decoder
+type countingReader struct {
+	r io.Reader
+	n uint64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += uint64(n)
+	return n, err
+}
+
+// sniffUtf16 - guess if a block without a BOM is UTF-16 by looking for the NUL byte of each ASCII code unit;
+// returns BomUtf16le, BomUtf16be or BomNone when the guess is not confident
+func sniffUtf16(block []byte) string {
+	var le, be, units int
+	for i := 0; i+1 < len(block); i += 2 {
+		units++
+		if block[i] != 0 && block[i+1] == 0 {
+			le++
+		} else if block[i] == 0 && block[i+1] != 0 {
+			be++
+		}
+	}
+	if units < utf16SniffMinUnits {
+		return BomNone
+	}
+
+	// require at least 60% of the code units to follow one byte order and almost none to follow the other
+	if le*10 >= units*6 && be*20 <= units {
+		return BomUtf16le
+	}
+	if be*10 >= units*6 && le*20 <= units {
+		return BomUtf16be
+	}
+	return BomNone
+}
+
+// decoderFor - return the encoding used to transcode input starting with the given BOM, or nil when the
+// input should be scanned byte-by-byte; when there is no BOM, firstBlock is examined for UTF-16
+func decoderFor(bom string, firstBlock []byte) (encoding.Encoding, string) {
+	switch bom {
+	case BomUtf16le:
+		return unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM), bom
+	case BomUtf16be:
+		return unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM), bom
+	case BomUtf32le:
+		return utf32.UTF32(utf32.LittleEndian, utf32.ExpectBOM), bom
+	case BomUtf32be:
+		return utf32.UTF32(utf32.BigEndian, utf32.ExpectBOM), bom
+	case BomNone:
+		switch sniffUtf16(firstBlock) {
+		case BomUtf16le:
+			return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), BomUtf16le
+		case BomUtf16be:
+			return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM), BomUtf16be
+		}
+	}
+	return nil, ""
+}
@@ -0,0 +1,206 @@
+package chars
+
+/*
+encoding.go
+
+Guess the character encoding of a file when -E is used
+
+A BOM, a confidently detected UTF-16 file, pure ASCII and valid UTF-8 are all decided from the
+counts gathered by searchForSpecialChars. Anything else is decoded with each legacy candidate
+and scored on how plausible the resulting text looks: replacement characters and C1 controls
+count against a candidate, letters in the expected script count for it, and a few shape rules
+(long runs of accented letters, mixed scripts within a word, mostly upper case text) catch
+text decoded with the wrong single-byte code page.
+*/
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
+// encodings reported in SpecialChars.Encoding
+const (
+	EncodingAscii       string = "ASCII"
+	EncodingUtf8        string = "UTF-8"
+	EncodingWindows1252 string = "Windows-1252"
+	EncodingIso88591    string = "ISO-8859-1"
+	EncodingWindows1251 string = "Windows-1251"
+	EncodingKoi8r       string = "KOI8-R"
+	EncodingShiftJis    string = "Shift_JIS"
+	EncodingEucJp       string = "EUC-JP"
+	EncodingGb18030     string = "GB18030"
+	EncodingBig5        string = "Big5"
+	EncodingEucKr       string = "EUC-KR"
+)
+
+// only this many bytes from blocks containing non-ASCII characters are kept for scoring legacy encodings
+const encodingSampleSize int = 64 * 1024
+
+// frequently used characters, which separate Chinese and Korean text from the same bytes decoded as another CJK encoding
+const (
+	commonSimplified  = "的一是不了在人有我他这个们中来上大为和国地到以说时要就出会可也你对生能而子那得于着下自之年过发后作里用道行所然家种事成方多经么去法学如都同现当没动面起看定天分还进好小部其些主样理心她本前开但因只从想实"
+	commonTraditional = "的一是不了在人有我他這個們中來上大為和國地到以說時要就出會可也你對生能而子那得於著下自之年過發後作裡用道行所然家種事成方多經麼去法學如都同現當沒動面起看定天分還進好小部其些主樣理心她本前開但因只從想實"
+	commonHangul      = "이다는의에하고을가지한기서로도리나사자대어수들게일보으니인해그있것적시를만요아"
+)
+
+// kinds of legacy encodings, which are each scored slightly differently
+const (
+	kindLatin    int = iota // single-byte Latin code page
+	kindCyrillic            // single-byte Cyrillic code page
+	kindCjk                 // multibyte Chinese, Japanese or Korean encoding
+)
+
+type encodingCandidate struct {
+	name    string
+	enc     encoding.Encoding
+	kind    int
+	scripts []*unicode.RangeTable // letters expected in text of this encoding
+	common  string                // frequently used characters; Hiragana is always common
+}
+
+// legacyCandidates - when two candidates decode a sample identically, the first one wins
+var legacyCandidates = []encodingCandidate{
+	{EncodingIso88591, charmap.ISO8859_1, kindLatin, []*unicode.RangeTable{unicode.Latin}, ""},
+	{EncodingWindows1252, charmap.Windows1252, kindLatin, []*unicode.RangeTable{unicode.Latin}, ""},
+	{EncodingWindows1251, charmap.Windows1251, kindCyrillic, []*unicode.RangeTable{unicode.Cyrillic}, ""},
+	{EncodingKoi8r, charmap.KOI8R, kindCyrillic, []*unicode.RangeTable{unicode.Cyrillic}, ""},
+	{EncodingShiftJis, japanese.ShiftJIS, kindCjk, []*unicode.RangeTable{unicode.Hiragana, unicode.Katakana, unicode.Han}, ""},
+	{EncodingEucJp, japanese.EUCJP, kindCjk, []*unicode.RangeTable{unicode.Hiragana, unicode.Katakana, unicode.Han}, ""},
+	{EncodingGb18030, simplifiedchinese.GB18030, kindCjk, []*unicode.RangeTable{unicode.Han}, commonSimplified},
+	{EncodingBig5, traditionalchinese.Big5, kindCjk, []*unicode.RangeTable{unicode.Han}, commonTraditional},
+	{EncodingEucKr, korean.EUCKR, kindCjk, []*unicode.RangeTable{unicode.Hangul, unicode.Han}, commonHangul},
+}
+
+// detectEncoding - return the most likely encoding of a file and a confidence between 0 and 1;
+// sample holds raw blocks of the file which contain non-ASCII bytes
+func detectEncoding(sc SpecialChars, sample []byte) (string, float64) {
+	if sc.Bom == BomGb18030 {
+		return EncodingGb18030, 1
+	}
+	if sc.Bom != BomNone {
+		return sc.Bom, 1
+	}
+	if sc.DecodedFrom != "" {
+		return sc.DecodedFrom, 0.8 // UTF-16 without a BOM, see sniffUtf16
+	}
+	if sc.NonAscii == 0 {
+		return EncodingAscii, 1
+	}
+
+	// valid multibyte UTF-8 is very unlikely to occur by accident
+	invalid := sc.InvalidUtf8()
+	if invalid == 0 {
+		return EncodingUtf8, roundConfidence(math.Min(1, 0.75+0.05*float64(sc.Utf8Multibyte)))
+	}
+
+	best, bestScore, runnerUp := "", 0.0, 0.0
+	var bestText string
+	for _, c := range legacyCandidates {
+		text, err := c.enc.NewDecoder().String(string(sample))
+		if err != nil {
+			continue
+		}
+		score := scoreDecoded(text, c)
+		if score > bestScore {
+			if text != bestText {
+				runnerUp = bestScore
+			}
+			best, bestScore, bestText = c.name, score, text
+		} else if score > runnerUp && text != bestText {
+			runnerUp = score
+		}
+	}
+
+	// mostly valid UTF-8 with a little corruption still beats a poor legacy match
+	utf8Score := float64(sc.Utf8Multibyte) / float64(sc.Utf8Multibyte+invalid)
+	if utf8Score >= bestScore {
+		return EncodingUtf8, roundConfidence(utf8Score * 0.9)
+	}
+
+	// reduce the confidence when another candidate is almost as plausible or there is little to go on
+	margin := math.Min(1, (bestScore-runnerUp)/0.25)
+	size := math.Min(1, 0.5+float64(sc.NonAscii)/20)
+	return best, roundConfidence(bestScore * (0.6 + 0.4*margin) * size)
+}
+
+// scoreDecoded - return how plausible text is between 0 and 1, based only on its non-ASCII characters
+func scoreDecoded(text string, c encodingCandidate) float64 {
+	var total, plausible, common, bad, upper, lower, anomalies int
+	run := 0       // consecutive non-ASCII characters
+	asciiWord := 0 // ASCII letters seen in the current word
+	scriptWord := 0
+
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			run = 0
+			if unicode.IsLetter(r) {
+				asciiWord++
+			} else {
+				if asciiWord > 0 && scriptWord > 0 && c.kind == kindCyrillic {
+					anomalies += scriptWord // Cyrillic letters mixed into an ASCII word
+				}
+				asciiWord, scriptWord = 0, 0
+			}
+			continue
+		}
+
+		total++
+		run++
+		switch {
+		case r == utf8.RuneError || unicode.IsControl(r) || unicode.Is(unicode.Co, r):
+			bad++
+			continue
+		case unicode.IsOneOf(c.scripts, r):
+			plausible++
+			scriptWord++
+			if unicode.IsUpper(r) {
+				upper++
+			} else if unicode.IsLower(r) {
+				lower++
+			}
+			if c.common != "" && strings.ContainsRune(c.common, r) {
+				common++
+			} else if unicode.Is(unicode.Hiragana, r) {
+				common++
+			}
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsNumber(r) || unicode.IsSpace(r):
+			plausible++
+			if c.kind != kindCjk && run > 1 {
+				anomalies++ // multibyte text decoded one byte at a time mixes letters and symbols
+			}
+		}
+		if c.kind == kindLatin && run > 2 {
+			anomalies++ // European text rarely has three non-ASCII characters in a row
+		}
+	}
+	if asciiWord > 0 && scriptWord > 0 && c.kind == kindCyrillic {
+		anomalies += scriptWord
+	}
+	if total == 0 {
+		return 0
+	}
+	if upper > lower {
+		anomalies += upper - lower // natural text is mostly lower case
+	}
+
+	score := float64(plausible-anomalies-2*bad) / float64(total)
+	if c.kind == kindCjk {
+		// several encodings share the same byte ranges, so favor the one producing common characters
+		score *= 0.5 + 0.5*math.Min(1, 4*float64(common)/float64(total))
+	}
+	return math.Max(0, math.Min(1, score))
+}
+
+// roundConfidence - two decimal places are plenty for a guess
+func roundConfidence(c float64) float64 {
+	return math.Round(c*100) / 100
+}
@@ -0,0 +1,435 @@
+package chars
+
+/*
+fix.go
+
+Rewrite files in place once they have been examined. Each fix is a stage of a streaming
+transform.Chain, so files larger than memory can be rewritten; the result is written to a
+temporary file in the same directory which then replaces the original with a rename. Files
+which would not change are never touched.
+*/
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/transform"
+)
+
+// FixResult - what the fix modes changed in a file, or would change with --dry-run
+type FixResult struct {
+	Changes     []string `json:"changes,omitempty"` // each change made, such as "eol=lf (12)"
+	Edits       uint64   `json:"edits"`
+	BytesBefore uint64   `json:"bytesBefore"`
+	BytesAfter  uint64   `json:"bytesAfter"`
+	Written     bool     `json:"written"`
+	DryRun      bool     `json:"dryRun,omitempty"`
+	Skipped     string   `json:"skipped,omitempty"` // why the file was left alone, such as binary
+	Error       string   `json:"error,omitempty"`
+}
+
+// fixStage - one step of the rewrite; edits is the number of changes made so far
+type fixStage interface {
+	transform.Transformer
+	name() string
+	edits() uint64
+}
+
+// Fixing - return true if any fix mode was requested
+func (opts Options) Fixing() bool {
+	return opts.FixEol != "" || opts.StripBom || opts.AddBom || opts.ToUtf8 ||
+		opts.ExpandTabs > 0 || opts.UnexpandTabs > 0 || opts.StripTrailingWs || opts.Normalize != ""
+}
+
+// ValidEol - return true if eol can be used with --fix-eol
+func ValidEol(eol string) bool {
+	return eol == EolLf || eol == EolCrlf
+}
+
+// summary - a short description for the table output
+func (fix *FixResult) summary() string {
+	switch {
+	case fix.Error != "":
+		return "error"
+	case fix.Skipped != "":
+		return "skipped: " + fix.Skipped
+	case fix.Edits == 0:
+		return "unchanged"
+	}
+
+	changes := strings.Join(fix.Changes, ", ")
+	if fix.BytesBefore != fix.BytesAfter {
+		changes += fmt.Sprintf(", %d->%d bytes", fix.BytesBefore, fix.BytesAfter)
+	}
+	if fix.DryRun {
+		return "would fix: " + changes
+	}
+	return "fixed: " + changes
+}
+
+// LookupEncoding - return the name of a legacy encoding which can be used with --from, such as windows-1252
+func LookupEncoding(name string) (string, error) {
+	_, canonical, err := legacyEncoding(name)
+	return canonical, err
+}
+
+// legacyEncoding - the encodings scored by -E are matched first so that they keep their familiar names
+func legacyEncoding(name string) (encoding.Encoding, string, error) {
+	for _, c := range legacyCandidates {
+		if strings.EqualFold(c.name, name) {
+			return c.enc, c.name, nil
+		}
+	}
+	enc, err := ianaindex.IANA.Encoding(name)
+	if err != nil {
+		return nil, "", err
+	}
+	if enc == nil {
+		return nil, "", fmt.Errorf("unsupported encoding: %s", name)
+	}
+	return enc, name, nil
+}
+
+// fixStages - the stages requested in opts, in the order they are applied; from is the
+// encoding of the file when it is transcoded to UTF-8 first
+func fixStages(opts Options, from encoding.Encoding, fromName string) []fixStage {
+	var stages []fixStage
+	if from != nil {
+		stages = append(stages, newTranscoder(from, fromName))
+	}
+	// NFKC can turn a no-break space into a space, which is then trailing whitespace
+	if opts.Normalize != "" {
+		stages = append(stages, newNormFixer(opts.Normalize))
+	}
+	if opts.FixEol != "" {
+		stages = append(stages, newEolFixer(opts.FixEol))
+	}
+	// trailing whitespace is removed first so that blank lines are not counted as indentation changes
+	if opts.StripTrailingWs {
+		stages = append(stages, &trailingStripper{})
+	}
+	if opts.ExpandTabs > 0 {
+		stages = append(stages, &tabExpander{size: opts.ExpandTabs})
+	} else if opts.UnexpandTabs > 0 {
+		stages = append(stages, &tabUnexpander{size: opts.UnexpandTabs})
+	}
+	// the BOM is examined last, so that it is checked at the start of the rewritten text
+	if opts.StripBom || opts.AddBom {
+		stages = append(stages, &bomFixer{add: opts.AddBom})
+	}
+	return stages
+}
+
+// fixSkipReason - files which can not be safely rewritten byte by byte are left alone
+// unless they are transcoded to UTF-8 first
+func fixSkipReason(stats SpecialChars, firstBlock []byte, from encoding.Encoding) string {
+	if !isText(firstBlock, len(firstBlock), true) {
+		return "binary"
+	}
+	if from != nil {
+		return ""
+	}
+	if stats.DecodedFrom != "" {
+		return "encoded as " + stats.DecodedFrom
+	}
+	if bomMatchesClass(stats.Bom, "bom16") || bomMatchesClass(stats.Bom, "bom32") {
+		return "encoded as " + stats.Bom
+	}
+	if wide := sniffUtf16(firstBlock); wide != BomNone {
+		return "encoded as " + wide
+	}
+	return ""
+}
+
+// sourceEncoding - with --to-utf8, return the encoding a file is transcoded from; a UTF-16 or UTF-32
+// BOM always wins over --from, and files which are already valid UTF-8 are left as they are
+func sourceEncoding(stats SpecialChars, firstBlock []byte, opts Options) (encoding.Encoding, string, string) {
+	if !opts.ToUtf8 {
+		return nil, "", ""
+	}
+	if enc, name := decoderFor(detectBom(firstBlock), firstBlock); enc != nil {
+		return enc, name, ""
+	}
+	if stats.InvalidUtf8() == 0 || bomMatchesClass(stats.Bom, "bom8") {
+		return nil, "", ""
+	}
+	if opts.FromEncoding == "" {
+		return nil, "", "not UTF-8, use --from"
+	}
+	enc, name, err := legacyEncoding(opts.FromEncoding)
+	if err != nil {
+		return nil, "", err.Error()
+	}
+	return enc, name, ""
+}
+
+// fixFile - apply the fix modes in opts to a file which has already been examined
+func fixFile(filename string, stats SpecialChars, opts Options) *FixResult {
+	fix := &FixResult{DryRun: opts.DryRun}
+	failed := func(err error) *FixResult {
+		fix.Error = err.Error()
+		_, _ = fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		return fix
+	}
+
+	// replace the file a symbolic link points to instead of the link itself
+	target, err := filepath.EvalSymlinks(filename)
+	if err != nil {
+		return failed(err)
+	}
+	in, err := os.Open(target)
+	if err != nil {
+		return failed(err)
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return failed(err)
+	}
+
+	firstBlock := make([]byte, BlockSize)
+	n, err := io.ReadFull(in, firstBlock)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return failed(err)
+	}
+	from, fromName, skipped := sourceEncoding(stats, firstBlock[:n], opts)
+	if skipped == "" {
+		skipped = fixSkipReason(stats, firstBlock[:n], from)
+	}
+	if fix.Skipped = skipped; fix.Skipped != "" {
+		return fix
+	}
+	if _, err = in.Seek(0, io.SeekStart); err != nil {
+		return failed(err)
+	}
+
+	stages := fixStages(opts, from, fromName)
+	if len(stages) == 0 {
+		return fix // such as --to-utf8 alone with a file that is already UTF-8
+	}
+	transformers := make([]transform.Transformer, len(stages))
+	for i, stage := range stages {
+		transformers[i] = stage
+	}
+	rdr := transform.NewReader(in, transform.Chain(transformers...))
+
+	out := &countingWriter{w: io.Discard}
+	var tmp *os.File
+	if !opts.DryRun {
+		tmp, err = os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".chars-*")
+		if err != nil {
+			return failed(err)
+		}
+		defer func() {
+			if !fix.Written {
+				_ = tmp.Close()
+				_ = os.Remove(tmp.Name())
+			}
+		}()
+		out.w = tmp
+	}
+	if _, err = io.Copy(out, rdr); err != nil {
+		return failed(err)
+	}
+	fix.BytesBefore, fix.BytesAfter = uint64(info.Size()), out.n
+	for _, stage := range stages {
+		if stage.edits() > 0 {
+			fix.Changes = append(fix.Changes, fmt.Sprintf("%s (%d)", stage.name(), stage.edits()))
+			fix.Edits += stage.edits()
+		}
+	}
+	if tc, ok := stages[0].(*transcoder); ok && tc.replaced > 0 && !opts.Lossy {
+		fix.Skipped = fmt.Sprintf("%d characters can not be converted from %s, use --lossy", tc.replaced, tc.from)
+		return fix
+	}
+	if fix.Edits == 0 || opts.DryRun {
+		return fix
+	}
+
+	if err = replaceFile(tmp, target, info, opts.PreserveMtime); err != nil {
+		return failed(err)
+	}
+	fix.Written = true
+	return fix
+}
+
+// countingWriter - keep track of the size of the rewritten file
+type countingWriter struct {
+	w io.Writer
+	n uint64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += uint64(n)
+	return n, err
+}
+
+// replaceFile - give tmp the permissions of the original file and rename it over target
+func replaceFile(tmp *os.File, target string, info os.FileInfo, preserveMtime bool) error {
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if preserveMtime {
+		if err := os.Chtimes(tmp.Name(), time.Time{}, info.ModTime()); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+// eolFixer - convert CRLF, LF and lone CR line endings to a single style
+type eolFixer struct {
+	to    string
+	eol   []byte
+	count uint64
+}
+
+func newEolFixer(to string) *eolFixer {
+	eol := []byte("\n")
+	if to == EolCrlf {
+		eol = []byte("\r\n")
+	}
+	return &eolFixer{to: to, eol: eol}
+}
+
+func (e *eolFixer) name() string  { return "eol=" + e.to }
+func (e *eolFixer) edits() uint64 { return e.count }
+func (e *eolFixer) Reset()        { e.count = 0 }
+
+// Transform - a CR at the end of src is held back until it is known whether LF follows it
+func (e *eolFixer) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for nSrc < len(src) {
+		// copy everything up to the next line ending
+		end := bytes.IndexAny(src[nSrc:], "\r\n")
+		if end != 0 {
+			if end < 0 {
+				end = len(src) - nSrc
+			}
+			n := copy(dst[nDst:], src[nSrc:nSrc+end])
+			nDst += n
+			nSrc += n
+			if n < end {
+				return nDst, nSrc, transform.ErrShortDst
+			}
+			continue
+		}
+
+		consumed := 1
+		changed := false
+		switch {
+		case src[nSrc] == '\n':
+			changed = e.to != EolLf
+		case nSrc+1 == len(src) && !atEOF:
+			return nDst, nSrc, transform.ErrShortSrc
+		case nSrc+1 < len(src) && src[nSrc+1] == '\n':
+			consumed = 2
+			changed = e.to != EolCrlf
+		default:
+			changed = true // lone CR
+		}
+		if len(dst)-nDst < len(e.eol) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		nDst += copy(dst[nDst:], e.eol)
+		nSrc += consumed
+		if changed {
+			e.count++
+		}
+	}
+	return nDst, nSrc, nil
+}
+
+// bomFixer - remove any byte order mark, or add a UTF-8 BOM when the text does not start with one
+type bomFixer struct {
+	add     bool
+	started bool
+	count   uint64
+}
+
+func (bf *bomFixer) name() string {
+	if bf.add {
+		return "add bom"
+	}
+	return "strip bom"
+}
+
+func (bf *bomFixer) edits() uint64 { return bf.count }
+func (bf *bomFixer) Reset()        { bf.started, bf.count = false, 0 }
+
+// Transform - only the start of the text is changed, everything else is copied as-is
+func (bf *bomFixer) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	if !bf.started {
+		if len(src) < bomMaxLength && !atEOF {
+			return 0, 0, transform.ErrShortSrc
+		}
+		bom := detectBom(src)
+		if bf.add && bom == BomNone {
+			if len(dst) < len(utf8Bom) {
+				return 0, 0, transform.ErrShortDst
+			}
+			nDst = copy(dst, utf8Bom)
+			bf.count++
+		} else if !bf.add && bom != BomNone {
+			nSrc = bomLength(bom)
+			bf.count++
+		}
+		bf.started = true
+	}
+
+	n := copy(dst[nDst:], src[nSrc:])
+	nDst += n
+	nSrc += n
+	if nSrc < len(src) {
+		return nDst, nSrc, transform.ErrShortDst
+	}
+	return nDst, nSrc, nil
+}
+
+// transcoder - decode the whole file to UTF-8; every character of a UTF-16 or UTF-32 file is an edit,
+// otherwise only the non-ASCII characters are; replaced counts characters which could not be decoded
+type transcoder struct {
+	from     string
+	dec      transform.Transformer
+	wide     bool
+	count    uint64
+	replaced uint64
+}
+
+func newTranscoder(enc encoding.Encoding, from string) *transcoder {
+	wide := from == BomUtf16le || from == BomUtf16be || from == BomUtf32le || from == BomUtf32be
+	return &transcoder{from: from, dec: enc.NewDecoder(), wide: wide}
+}
+
+func (tc *transcoder) name() string  { return "utf8 from " + tc.from }
+func (tc *transcoder) edits() uint64 { return tc.count }
+
+func (tc *transcoder) Reset() {
+	tc.dec.Reset()
+	tc.count, tc.replaced = 0, 0
+}
+
+// Transform - decoders only ever write complete characters, so dst can be examined after each call
+func (tc *transcoder) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	nDst, nSrc, err = tc.dec.Transform(dst, src, atEOF)
+	for _, r := range string(dst[:nDst]) {
+		if tc.wide || r >= utf8.RuneSelf {
+			tc.count++
+		}
+		if r == utf8.RuneError {
+			tc.replaced++
+		}
+	}
+	return nDst, nSrc, err
+}
@@ -0,0 +1,132 @@
+package chars
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/text/transform"
+)
+
+// transformChunks - run t over input the way a transform.Reader with tiny buffers would: at most srcSize
+// new bytes of input are offered at a time, with room for dstSize bytes of output
+func transformChunks(t transform.Transformer, input []byte, srcSize, dstSize int) ([]byte, error) {
+	t.Reset()
+	var out, src []byte
+	dst := make([]byte, dstSize)
+	for {
+		n := min(srcSize, len(input))
+		src, input = append(src, input[:n]...), input[n:]
+		atEOF := len(input) == 0
+		nDst, nSrc, err := t.Transform(dst, src, atEOF)
+		out = append(out, dst[:nDst]...)
+		src = src[nSrc:]
+		switch {
+		case err == nil && atEOF:
+			if len(src) > 0 {
+				return out, fmt.Errorf("%d bytes were not consumed at EOF", len(src))
+			}
+			return out, nil
+		case err == nil, err == transform.ErrShortSrc && !atEOF:
+		case err == transform.ErrShortDst && (nDst > 0 || nSrc > 0):
+		default:
+			return out, fmt.Errorf("no progress with %d bytes left: %v", len(src), err)
+		}
+	}
+}
+
+// checkStage - the output of stage must be want, with the given number of edits, whether the input is
+// transformed all at once or a few bytes at a time
+func checkStage(t *testing.T, stage fixStage, input, want string, edits uint64) {
+	t.Helper()
+	got, _, err := transform.Bytes(stage, []byte(input))
+	if err != nil {
+		t.Fatalf("%s %q: %v", stage.name(), input, err)
+	}
+	if string(got) != want || stage.edits() != edits {
+		t.Errorf("%s %q = %q with %d edits, want %q with %d edits", stage.name(), input, got, stage.edits(), want, edits)
+	}
+
+	for _, srcSize := range []int{1, 2, 3, 7} {
+		for _, dstSize := range []int{8, 64} {
+			got, err := transformChunks(stage, []byte(input), srcSize, dstSize)
+			if err != nil {
+				t.Fatalf("%s %q src %d dst %d: %v", stage.name(), input, srcSize, dstSize, err)
+			}
+			if string(got) != want || stage.edits() != edits {
+				t.Errorf("%s %q src %d dst %d = %q with %d edits, want %q with %d edits", stage.name(), input,
+					srcSize, dstSize, got, stage.edits(), want, edits)
+			}
+		}
+	}
+}
+
+func TestEolFixer(t *testing.T) {
+	tests := []struct {
+		to, input, want string
+		edits           uint64
+	}{
+		{EolLf, "a\r\nb\r\n", "a\nb\n", 2},
+		{EolLf, "a\rb\r", "a\nb\n", 2},
+		{EolLf, "a\nb\n", "a\nb\n", 0},
+		{EolCrlf, "a\nb\rc\r\n", "a\r\nb\r\nc\r\n", 2},
+		{EolCrlf, "\r", "\r\n", 1},
+		{EolLf, "\r\r\n\n\r", "\n\n\n\n", 3},
+		// the CR is held back at the end of a block until the LF after it is read
+		{EolLf, "abcdef\r\nghijkl\r\n", "abcdef\nghijkl\n", 2},
+		{EolCrlf, "abcdef\r\nghijkl\r\n", "abcdef\r\nghijkl\r\n", 0},
+		{EolLf, "", "", 0},
+	}
+	for _, tt := range tests {
+		checkStage(t, newEolFixer(tt.to), tt.input, tt.want, tt.edits)
+	}
+}
+
+func TestBomFixer(t *testing.T) {
+	tests := []struct {
+		add         bool
+		input, want string
+		edits       uint64
+	}{
+		{true, "abc\n", "\xef\xbb\xbfabc\n", 1},
+		{true, "\xef\xbb\xbfabc\n", "\xef\xbb\xbfabc\n", 0},
+		{false, "\xef\xbb\xbfabc\n", "abc\n", 1},
+		{false, "abc\n", "abc\n", 0},
+		// files shorter than the longest BOM are only examined at EOF
+		{true, "", "\xef\xbb\xbf", 1},
+		{true, "a", "\xef\xbb\xbfa", 1},
+		{true, "\xef\xbb\xbf", "\xef\xbb\xbf", 0},
+		{false, "\xef\xbb\xbf", "", 1},
+		{false, "\xef\xbb", "\xef\xbb", 0},
+	}
+	for _, tt := range tests {
+		checkStage(t, &bomFixer{add: tt.add}, tt.input, tt.want, tt.edits)
+	}
+}
+
+func TestTranscoder(t *testing.T) {
+	utf16le, from := decoderFor(BomUtf16le, nil)
+	utf16be, _ := decoderFor(BomUtf16be, nil)
+	checkStage(t, newTranscoder(utf16le, from), "\xff\xfea\x00\xe9\x00\n\x00", "aé\n", 3)
+	checkStage(t, newTranscoder(utf16be, BomUtf16be), "\xfe\xff\x00a\xd8\x3d\xde\x00\x00\n", "a\U0001f600\n", 3)
+
+	// only the non-ASCII characters of a legacy encoding are edits
+	tests := []struct {
+		encoding, input, want string
+		edits, replaced       uint64
+	}{
+		{"windows-1252", "caf\xe9 \x80\n", "café €\n", 2, 0},
+		{"Shift_JIS", "\x93\xfa\x96\x7b\n", "日本\n", 2, 0},
+		{"Shift_JIS", "a\x81\n", "a\ufffd\n", 1, 1},
+	}
+	for _, tt := range tests {
+		enc, name, err := legacyEncoding(tt.encoding)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tc := newTranscoder(enc, name)
+		checkStage(t, tc, tt.input, tt.want, tt.edits)
+		if tc.replaced != tt.replaced {
+			t.Errorf("%s %q: %d characters replaced, want %d", tt.encoding, tt.input, tc.replaced, tt.replaced)
+		}
+	}
+}
@@ -4,8 +4,7 @@ go 1.24.2
 
 require (
 	github.com/jftuga/ellipsis v1.0.0
+	github.com/mattn/go-runewidth v0.0.9
 	github.com/olekukonko/tablewriter v0.0.5
 	golang.org/x/text v0.24.0
 )
-
-require github.com/mattn/go-runewidth v0.0.9 // indirect
@@ -0,0 +1,116 @@
+package chars
+
+/*
+histogram.go
+
+Count each distinct non-ASCII character for --histogram, so that a large NonAscii count can be
+explained, such as the curly quotes and em dashes left behind by a word processor
+*/
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"unicode"
+
+	"github.com/jftuga/ellipsis"
+	"github.com/olekukonko/tablewriter"
+	"golang.org/x/text/unicode/runenames"
+)
+
+// orders accepted by --histogram-sort
+const (
+	HistogramSortCount     string = "count"
+	HistogramSortCodePoint string = "codepoint"
+)
+
+// HistogramEntry - how many times a non-ASCII character was found; Category is its Unicode general category, such as Pd
+type HistogramEntry struct {
+	CodePoint string `json:"codePoint"`
+	Name      string `json:"name"`
+	Category  string `json:"category"`
+	Count     uint64 `json:"count"`
+	r         rune
+}
+
+// generalCategories - the two letter general categories, which do not overlap once LC is left out
+var generalCategories []string
+
+func init() {
+	for name := range unicode.Categories {
+		if len(name) == 2 && name != "LC" {
+			generalCategories = append(generalCategories, name)
+		}
+	}
+	sort.Strings(generalCategories)
+}
+
+// ValidHistogramSort - return true when order can be used with --histogram-sort
+func ValidHistogramSort(order string) bool {
+	return order == HistogramSortCount || order == HistogramSortCodePoint
+}
+
+// generalCategory - the general category of r; Cn for characters which are not assigned
+func generalCategory(r rune) string {
+	for _, name := range generalCategories {
+		if unicode.Is(unicode.Categories[name], r) {
+			return name
+		}
+	}
+	return "Cn"
+}
+
+// buildHistogram - one entry for each character in counts, ordered by code point or by count,
+// from the most to the least common
+func buildHistogram(counts map[rune]uint64, order string) []HistogramEntry {
+	if len(counts) == 0 {
+		return nil
+	}
+	histogram := make([]HistogramEntry, 0, len(counts))
+	for r, count := range counts {
+		histogram = append(histogram, HistogramEntry{CodePoint: fmt.Sprintf("U+%04X", r), Name: runenames.Name(r),
+			Category: generalCategory(r), Count: count, r: r})
+	}
+	sort.Slice(histogram, func(i, j int) bool {
+		if order == HistogramSortCount && histogram[i].Count != histogram[j].Count {
+			return histogram[i].Count > histogram[j].Count
+		}
+		return histogram[i].r < histogram[j].r
+	})
+	return histogram
+}
+
+// histogramChar - the character itself, unless it would not be visible on its own
+func histogramChar(r rune) string {
+	if !unicode.IsGraphic(r) || unicode.Is(unicode.M, r) || unicode.IsSpace(r) {
+		return ""
+	}
+	return string(r)
+}
+
+// OutputHistogram - display a text table with each non-ASCII character found in each file
+func OutputHistogram(allStats []SpecialChars, maxLength int, wantCommas bool) error {
+	if len(allStats) == 0 {
+		return nil
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"filename", "code point", "char", "name", "category", "count"})
+	table.SetAutoWrapText(false)
+	var name string
+	for _, s := range allStats {
+		if maxLength == 0 {
+			name = s.Filename
+		} else {
+			name = ellipsis.Shorten(s.Filename, maxLength)
+		}
+		for _, entry := range s.Histogram {
+			table.Append([]string{name, entry.CodePoint, histogramChar(entry.r), entry.Name, entry.Category,
+				formatCount(entry.Count, wantCommas)})
+		}
+	}
+	table.Render()
+	return w.Flush()
+}
@@ -0,0 +1,246 @@
+package chars
+
+/*
+ignore.go
+
+Honor .gitignore, .git/info/exclude, the global git excludes file and .charsignore while
+walking a directory with -r; use --no-ignore to examine everything
+https://git-scm.com/docs/gitignore#_pattern_format
+*/
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// per-directory ignore files, in increasing order of precedence
+var ignoreFileNames = []string{".gitignore", ".charsignore"}
+
+// ignoreRule - a single line of an ignore file; base is the directory the pattern is relative to
+type ignoreRule struct {
+	base     string
+	re       *regexp.Regexp
+	negate   bool
+	dirOnly  bool
+	anchored bool // the pattern contains a / and is matched against the path relative to base
+}
+
+// ignoreMatcher - top is the root of the git repository, or the walked directory when not in a repository
+type ignoreMatcher struct {
+	top    string
+	global []ignoreRule
+	perDir map[string][]ignoreRule
+}
+
+// newIgnoreMatcher - load the repository wide ignore files for the directory about to be walked
+func newIgnoreMatcher(root string) *ignoreMatcher {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		abs = root
+	}
+	m := &ignoreMatcher{top: abs, perDir: make(map[string][]ignoreRule)}
+	repo := findRepoRoot(abs)
+	if repo == "" {
+		return m
+	}
+	m.top = repo
+	if excludesFile := globalExcludesFile(); excludesFile != "" {
+		m.global = append(m.global, loadIgnoreFile(excludesFile, repo)...)
+	}
+	m.global = append(m.global, loadIgnoreFile(filepath.Join(repo, ".git", "info", "exclude"), repo)...)
+	return m
+}
+
+// findRepoRoot - return the closest directory at or above dir which contains .git, or an empty string
+func findRepoRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// globalExcludesFile - core.excludesFile from the user's git config, or git's default location
+func globalExcludesFile() string {
+	home, _ := os.UserHomeDir()
+	xdg := os.Getenv("XDG_CONFIG_HOME")
+	if xdg == "" && home != "" {
+		xdg = filepath.Join(home, ".config")
+	}
+
+	var configs []string
+	if xdg != "" {
+		configs = append(configs, filepath.Join(xdg, "git", "config"))
+	}
+	if home != "" {
+		configs = append(configs, filepath.Join(home, ".gitconfig"))
+	}
+	excludesFile := ""
+	for _, config := range configs {
+		if value := readGitConfigValue(config, "core", "excludesfile"); value != "" {
+			excludesFile = value // ~/.gitconfig takes precedence, so it is read last
+		}
+	}
+	if strings.HasPrefix(excludesFile, "~/") && home != "" {
+		excludesFile = filepath.Join(home, excludesFile[2:])
+	}
+	if excludesFile == "" && xdg != "" {
+		excludesFile = filepath.Join(xdg, "git", "ignore")
+	}
+	return excludesFile
+}
+
+// readGitConfigValue - a minimal reader for a single key of a git config file
+func readGitConfigValue(filename, section, key string) string {
+	file, err := os.Open(filename)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	value := ""
+	current := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' {
+			current = strings.ToLower(strings.Trim(strings.Fields(line)[0], "[]"))
+			continue
+		}
+		name, val, found := strings.Cut(line, "=")
+		if found && current == section && strings.ToLower(strings.TrimSpace(name)) == key {
+			value = strings.Trim(strings.TrimSpace(val), `"`)
+		}
+	}
+	return value
+}
+
+// loadIgnoreFile - parse each pattern of an ignore file; a missing file has no rules
+func loadIgnoreFile(filename, base string) []ignoreRule {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok := parseIgnorePattern(scanner.Text(), base); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// parseIgnorePattern - convert one line of an ignore file using the gitignore rules
+func parseIgnorePattern(line, base string) (ignoreRule, bool) {
+	rule := ignoreRule{base: base}
+	line = strings.TrimSuffix(line, "\r")
+
+	// trailing spaces are ignored unless escaped with a backslash
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	if line == "" || line[0] == '#' {
+		return rule, false
+	}
+	if line[0] == '!' {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return rule, false
+	}
+	rule.anchored = strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	re, err := globToRegexp(line)
+	if err != nil {
+		return rule, false
+	}
+	rule.re = re
+	return rule, true
+}
+
+// rulesFor - the rules from the ignore files found in dir, which are only read once
+func (m *ignoreMatcher) rulesFor(dir string) []ignoreRule {
+	rules, ok := m.perDir[dir]
+	if ok {
+		return rules
+	}
+	for _, name := range ignoreFileNames {
+		rules = append(rules, loadIgnoreFile(filepath.Join(dir, name), dir)...)
+	}
+	m.perDir[dir] = rules
+	return rules
+}
+
+// ignored - return true if the file or directory at p should be skipped; the last matching rule wins
+// and rules in deeper directories are applied after those in their parents
+func (m *ignoreMatcher) ignored(p string, isDir bool) bool {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return false
+	}
+
+	// directories from the top down to the one containing p
+	var dirs []string
+	dir := filepath.Dir(abs)
+	for {
+		dirs = append([]string{dir}, dirs...)
+		parent := filepath.Dir(dir)
+		if dir == m.top || parent == dir {
+			break
+		}
+		dir = parent
+	}
+	if dirs[0] != m.top {
+		dirs = dirs[len(dirs)-1:] // outside of the repository, such as a followed symbolic link
+	}
+
+	ignore := m.apply(m.global, abs, isDir, false)
+	for _, dir := range dirs {
+		ignore = m.apply(m.rulesFor(dir), abs, isDir, ignore)
+	}
+	return ignore
+}
+
+// apply - return the result of the last rule matching abs, or ignore when none match
+func (m *ignoreMatcher) apply(rules []ignoreRule, abs string, isDir bool, ignore bool) bool {
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		rel, err := filepath.Rel(rule.base, abs)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		target := rel
+		if !rule.anchored {
+			target = filepath.Base(abs)
+		}
+		if rule.re.MatchString(target) {
+			ignore = !rule.negate
+		}
+	}
+	return ignore
+}
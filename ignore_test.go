@@ -0,0 +1,96 @@
+package chars
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParseIgnorePattern(t *testing.T) {
+	tests := []struct {
+		line                      string
+		ok, negate, dir, anchored bool
+		name                      string // matched by the rule, unless empty
+	}{
+		{"", false, false, false, false, ""},
+		{"# comment", false, false, false, false, ""},
+		{"   ", false, false, false, false, ""},
+		{"*.log", true, false, false, false, "debug.log"},
+		{"*.log   ", true, false, false, false, "debug.log"},
+		{`a\ `, true, false, false, false, "a "},
+		{"!keep.log", true, true, false, false, "keep.log"},
+		{`\!important`, true, false, false, false, "!important"},
+		{`\#file`, true, false, false, false, "#file"},
+		{"build/", true, false, true, false, "build"},
+		{"/build", true, false, false, true, "build"},
+		{"docs/*.txt", true, false, false, true, "docs/a.txt"},
+		{"!/out/", true, true, true, true, "out"},
+		{"*.log\r", true, false, false, false, "debug.log"},
+		{"/", false, false, true, false, ""},
+		{"[abc", false, false, false, false, ""},
+	}
+	for _, tt := range tests {
+		rule, ok := parseIgnorePattern(tt.line, "base")
+		if ok != tt.ok {
+			t.Errorf("%q: ok = %v, want %v", tt.line, ok, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if rule.negate != tt.negate || rule.dirOnly != tt.dir || rule.anchored != tt.anchored {
+			t.Errorf("%q: negate %v, dirOnly %v, anchored %v, want %v, %v, %v", tt.line,
+				rule.negate, rule.dirOnly, rule.anchored, tt.negate, tt.dir, tt.anchored)
+		}
+		if !rule.re.MatchString(tt.name) {
+			t.Errorf("%q does not match %q", tt.line, tt.name)
+		}
+	}
+}
+
+// TestIgnoreMatcherApply - the last matching rule wins, so a negated rule can bring back a file which
+// an earlier rule ignored; anchored rules only match the path relative to the directory of their file
+func TestIgnoreMatcherApply(t *testing.T) {
+	top := filepath.Join(t.TempDir(), "repo")
+	var rules []ignoreRule
+	for _, line := range []string{"*.log", "!keep.log", "/build", "docs/*.txt", "tmp/"} {
+		rule, ok := parseIgnorePattern(line, top)
+		if !ok {
+			t.Fatalf("%q was not parsed", line)
+		}
+		rules = append(rules, rule)
+	}
+	m := &ignoreMatcher{top: top}
+
+	tests := []struct {
+		rel    string
+		isDir  bool
+		ignore bool
+	}{
+		{"debug.log", false, true},
+		{"src/debug.log", false, true},
+		{"keep.log", false, false},
+		{"src/keep.log", false, false},
+		{"build", true, true},
+		{"build", false, true},
+		{"src/build", true, false},
+		{"docs/a.txt", false, true},
+		{"src/docs/a.txt", false, false},
+		{"docs/a.md", false, false},
+		{"tmp", true, true},
+		{"tmp", false, false},
+		{"src/tmp", true, true},
+		{"main.go", false, false},
+	}
+	for _, tt := range tests {
+		abs := filepath.Join(top, filepath.FromSlash(tt.rel))
+		if got := m.apply(rules, abs, tt.isDir, false); got != tt.ignore {
+			t.Errorf("%s (directory %v): ignored = %v, want %v", tt.rel, tt.isDir, got, tt.ignore)
+		}
+	}
+
+	// a file outside of the directory of the rules is left as it was
+	outside := filepath.Join(filepath.Dir(top), "debug.log")
+	if !m.apply(rules, outside, false, true) || m.apply(rules, outside, false, false) {
+		t.Errorf("rules for %s were applied to %s", top, outside)
+	}
+}
@@ -0,0 +1,283 @@
+package chars
+
+/*
+lines.go
+
+Keep track of each line examined by searchForSpecialChars: lines ending with spaces or tabs,
+blank lines at the end of the file, whether the file ends with a newline, how each line is
+indented and how long it is. LF, CRLF and a lone CR all end a line.
+*/
+
+import (
+	"bytes"
+	"math"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// indentation styles reported in SpecialChars.Indent
+const (
+	IndentNone   string = "none"
+	IndentTabs   string = "tabs"
+	IndentSpaces string = "spaces"
+	IndentMixed  string = "mixed"
+)
+
+// the largest difference in indentation between two lines used to infer the indent width
+const maxIndentWidth int = 8
+
+// a tab advances to the next multiple of this many display columns, as it does in a terminal
+const displayTabWidth uint64 = 8
+
+// display widths do not depend on the locale, so that results are the same everywhere
+var displayWidth = &runewidth.Condition{EastAsianWidth: false}
+
+// lineTracker - measures each line; a line can span more than one read
+type lineTracker struct {
+	wsRun      bool     // the line currently ends with a space or tab
+	wsStart    Location // where that run of whitespace started
+	blank      bool     // the line so far only has spaces and tabs
+	empty      bool     // nothing other than a line ending has been seen on this line
+	blankStart Location // the first of the current run of blank lines
+	seen       bool     // anything other than a byte order mark has been examined
+	trailingWs uint64
+	blankRun   uint64
+
+	// indentation of the current line, until its first other character is found
+	lineStart     Location
+	leading       bool
+	leadTabs      bool
+	leadSpaces    int
+	firstStyle    string // style of the first indented line, which the others are expected to follow
+	previousWidth int    // number of spaces indenting the previous line, or -1 when it used tabs
+	widths        [maxIndentWidth + 1]uint64
+	tabLines      uint64
+	spaceLines    uint64
+	mixedLines    uint64
+
+	// length of the current line, not including its line ending
+	limit        uint64 // lines longer than this many columns are counted in longLines; 0 is unlimited
+	lineBytes    uint64
+	lineColumns  uint64
+	long         bool
+	lines        uint64
+	maxBytes     uint64
+	maxColumns   uint64
+	totalColumns uint64
+	longLines    uint64
+}
+
+func newLineTracker(limit int) lineTracker {
+	return lineTracker{blank: true, empty: true, leading: true, limit: uint64(max(0, limit))}
+}
+
+// measure - add the display width of a byte of the current line; r is set once a character is
+// complete and each invalid UTF-8 sequence is displayed as a single replacement character
+func (lt *lineTracker) measure(loc *locator, r rune, complete bool, invalid uint64) {
+	lt.lineBytes++
+	width := invalid
+	switch {
+	case !complete:
+	case r >= ' ' && r < 0x7f:
+		width++ // printable ASCII, which is most text, does not need a table lookup
+	case r == '\t':
+		width += displayTabWidth - lt.lineColumns%displayTabWidth
+	default:
+		width += uint64(displayWidth.RuneWidth(r))
+	}
+	lt.lineColumns += width
+	if lt.lineColumns > lt.limit && lt.limit > 0 && !lt.long {
+		lt.longLine(loc, loc.pos)
+	}
+}
+
+// addRun - the run of printable ASCII starting at position at; this does what measure and add would do
+// for each of its bytes
+func (lt *lineTracker) addRun(loc *locator, run []byte, at Location) {
+	n := uint64(len(run))
+	lt.lineBytes += n
+	if lt.lineColumns+n > lt.limit && lt.limit > 0 && !lt.long {
+		past := lt.limit - lt.lineColumns // the first character past the limit
+		lt.longLine(loc, at.advance(past))
+	}
+	lt.lineColumns += n
+
+	for lt.leading && len(run) > 0 {
+		lt.addSpace(loc, run[0], at) // until the line is no longer indented
+		run, at = run[1:], at.advance(1)
+	}
+	if len(run) == 0 {
+		return
+	}
+	n = uint64(len(run))
+	spaces := n - uint64(len(bytes.TrimRight(run, " ")))
+	switch {
+	case spaces == 0:
+		lt.wsRun = false
+	case spaces < n:
+		lt.wsRun = true
+		lt.wsStart = at.advance(n - spaces)
+	case !lt.wsRun:
+		lt.wsRun, lt.wsStart = true, at
+	}
+}
+
+// longLine - the current line became longer than the limit at position at
+func (lt *lineTracker) longLine(loc *locator, at Location) {
+	lt.long = true
+	lt.longLines++
+	loc.record("longlines", at)
+}
+
+// add - byte b of the current line, which is not part of a line ending, is at position at
+func (lt *lineTracker) add(loc *locator, b byte, at Location) {
+	if !lt.leading && b != ' ' && b != '\t' {
+		lt.wsRun = false // the line has already started, so it is neither empty nor blank
+		return
+	}
+	lt.addSpace(loc, b, at)
+}
+
+// addSpace - byte b is a space or tab, or is found before the first character of the line
+func (lt *lineTracker) addSpace(loc *locator, b byte, at Location) {
+	if lt.empty {
+		lt.lineStart = at
+	}
+	lt.empty, lt.seen = false, true
+	if lt.leading {
+		switch b {
+		case '\t':
+			lt.leadTabs = true
+		case ' ':
+			lt.leadSpaces++
+		default:
+			lt.leading = false
+			lt.indented(loc)
+		}
+	}
+	if b == ' ' || b == '\t' {
+		if !lt.wsRun {
+			lt.wsRun = true
+			lt.wsStart = at
+		}
+		return
+	}
+	lt.wsRun = false
+	lt.blank = false
+}
+
+// end - the line ending at position at was found
+func (lt *lineTracker) end(loc *locator, at Location) {
+	lt.seen = true
+	if lt.wsRun {
+		lt.trailingWs++
+		loc.record("trailingws", lt.wsStart)
+	}
+	if !lt.blank {
+		if lt.blankRun > 0 {
+			loc.holdContext("trailingblank", 0)
+		}
+		lt.blankRun = 0
+	} else if lt.blankRun++; lt.blankRun == 1 {
+		lt.blankStart = Location{Line: at.Line, Column: 1, Offset: at.Offset - (at.Column - 1)}
+		loc.holdContext("trailingblank", at.Line)
+	}
+	lt.wsRun, lt.blank, lt.empty = false, true, true
+	lt.leading, lt.leadTabs, lt.leadSpaces = true, false, 0
+
+	lt.lines++
+	lt.maxBytes = max(lt.maxBytes, lt.lineBytes)
+	lt.maxColumns = max(lt.maxColumns, lt.lineColumns)
+	lt.totalColumns += lt.lineColumns
+	lt.lineBytes, lt.lineColumns, lt.long = 0, 0, false
+}
+
+// averageColumns - the average display width of a line, to one decimal place
+func (lt *lineTracker) averageColumns() float64 {
+	if lt.lines == 0 {
+		return 0
+	}
+	return math.Round(float64(lt.totalColumns)/float64(lt.lines)*10) / 10
+}
+
+// indented - the first character of a line which is not blank was found; a line with mixed
+// indentation, or indented differently than the first indented line, is located as mixedindent
+func (lt *lineTracker) indented(loc *locator) {
+	style := IndentNone
+	switch {
+	case lt.leadTabs && lt.leadSpaces > 0:
+		style = IndentMixed
+		lt.mixedLines++
+	case lt.leadTabs:
+		style = IndentTabs
+		lt.tabLines++
+	case lt.leadSpaces > 0:
+		style = IndentSpaces
+		lt.spaceLines++
+	}
+	if style != IndentNone && lt.firstStyle == "" {
+		lt.firstStyle = style
+	}
+	if style == IndentMixed || style != IndentNone && style != lt.firstStyle {
+		loc.record("mixedindent", lt.lineStart)
+	}
+
+	// the difference in indentation between consecutive lines indented with spaces
+	width := -1
+	if !lt.leadTabs {
+		width = lt.leadSpaces
+	}
+	if width >= 0 && lt.previousWidth >= 0 {
+		if delta := width - lt.previousWidth; delta != 0 && max(delta, -delta) <= maxIndentWidth {
+			lt.widths[max(delta, -delta)]++
+		}
+	}
+	lt.previousWidth = width
+}
+
+// indentStyle - return a single verdict describing how lines are indented, similar to eolStyle
+func (lt *lineTracker) indentStyle() string {
+	switch {
+	case lt.mixedLines > 0 || lt.tabLines > 0 && lt.spaceLines > 0:
+		return IndentMixed
+	case lt.tabLines > 0:
+		return IndentTabs
+	case lt.spaceLines > 0:
+		return IndentSpaces
+	}
+	return IndentNone
+}
+
+// indentWidth - the most common difference in indentation between lines indented with spaces,
+// preferring the smaller width on a tie; 0 when no lines are indented with spaces
+func (lt *lineTracker) indentWidth() int {
+	if lt.spaceLines == 0 {
+		return 0
+	}
+	best := 0
+	for width := 1; width <= maxIndentWidth; width++ {
+		if lt.widths[width] > lt.widths[best] {
+			best = width
+		}
+	}
+	return best
+}
+
+// finish - the end of the input was reached; last is the final byte examined
+// returns true when the input has more than a byte order mark and does not end with a newline
+func (lt *lineTracker) finish(loc *locator, last byte) bool {
+	noFinalNewline := lt.seen && last != '\n' && last != '\r'
+	if !lt.empty {
+		lt.end(loc, loc.pos)
+	}
+	if noFinalNewline {
+		at := loc.pos
+		at.Column++
+		at.Offset++
+		loc.record("nofinalnl", at)
+	}
+	if lt.blankRun > 0 {
+		loc.record("trailingblank", lt.blankStart)
+	}
+	return noFinalNewline
+}
@@ -0,0 +1,182 @@
+package chars
+
+/*
+locations.go
+
+Track the line and column of every byte examined by searchForSpecialChars so that
+--locations can report where each offending character was found
+*/
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Location - where a character of the given class was found; Line and Column start at 1
+// Column counts characters, not bytes; Offset is in bytes from the start of the examined text
+type Location struct {
+	Line   uint64 `json:"line"`
+	Column uint64 `json:"column"`
+	Offset uint64 `json:"offset"`
+	Class  string `json:"class"`
+}
+
+// advance - the position n characters after at on the same line, for single byte characters
+func (at Location) advance(n uint64) Location {
+	return Location{Line: at.Line, Column: at.Column + n, Offset: at.Offset + n}
+}
+
+// DefaultMaxLocations - the number of locations kept for each file unless overridden with --max-locations
+const DefaultMaxLocations int = 100
+
+// defaultLocationClasses - recorded with --locations when -f is not used
+var defaultLocationClasses = []string{"crlf", "cr", "nul", "nonascii", "invalidutf8", "bidi", "invisible", "bom"}
+
+// locatedAs - -f classes which are located as other classes, such as controls which fails on any of them
+var locatedAs = map[string][]string{
+	"controls": {"ff", "vt", "bs", "esc", "del", "c0", "c1"},
+	"maxline":  {"longlines"},
+}
+
+// locator - the position of the byte being examined and the locations recorded for each class
+type locator struct {
+	pos        Location // position of the byte being examined
+	enabled    bool     // locations are recorded
+	tracking   bool     // positions are kept, for locations or for where each confusable was first found
+	wanted     map[string]bool
+	bomClasses []string
+	max        int
+	found      []Location
+	omitted    uint64
+	context    *contextTracker // nil unless --context is used
+}
+
+// newLocator - classes are the same names used with -f; unknown names are ignored
+func newLocator(opts Options) locator {
+	loc := locator{pos: Location{Line: 1}, enabled: opts.Locations, tracking: opts.Locations || opts.Confusables,
+		wanted: make(map[string]bool), max: opts.MaxLocations}
+	if !loc.enabled {
+		return loc
+	}
+	if opts.Context {
+		loc.context = newContextTracker(max(0, opts.ContextLines))
+	}
+
+	classes := opts.LocationClasses
+	if len(classes) == 0 {
+		classes = defaultLocationClasses
+	}
+	for _, class := range classes {
+		class = strings.ToLower(strings.TrimSpace(class))
+		if isBomClass(class) {
+			loc.bomClasses = append(loc.bomClasses, class)
+			continue
+		}
+		for _, located := range locatedAs[class] {
+			loc.wanted[located] = true
+		}
+		loc.wanted[class] = true
+	}
+	return loc
+}
+
+// advance - move to byte b, which follows last; both LF and a lone CR start a new line
+// continuation bytes of a UTF-8 character do not start a new column
+// positions are only kept when something reports them
+func (loc *locator) advance(b, last byte, offset uint64) {
+	if !loc.tracking {
+		return
+	}
+	if last == '\n' || last == '\r' && b != '\n' {
+		loc.pos.Line++
+		loc.pos.Column = 0
+		if loc.context != nil {
+			loc.context.endLine()
+		}
+	}
+	if b < 0x80 || b >= 0xc0 {
+		loc.pos.Column++
+	}
+	loc.pos.Offset = offset
+	if loc.context != nil {
+		loc.context.add(b)
+	}
+}
+
+// record - keep the location of a wanted class until the per file maximum is reached
+func (loc *locator) record(class string, at Location) {
+	if !loc.enabled || !loc.wanted[class] {
+		return
+	}
+	if loc.max > 0 && len(loc.found) >= loc.max {
+		loc.omitted++
+		return
+	}
+	at.Class = class
+	loc.found = append(loc.found, at)
+	if loc.context != nil {
+		loc.context.mark(at)
+	}
+}
+
+// discard - forget the locations recorded for class, which turned out not to apply
+func (loc *locator) discard(class string) {
+	found := loc.found[:0]
+	for _, at := range loc.found {
+		if at.Class != class {
+			found = append(found, at)
+		} else if loc.context != nil {
+			loc.context.unmark(at)
+		}
+	}
+	loc.found = found
+}
+
+// holdContext - with --context, keep the lines starting with line until released with 0, when class
+// may be recorded for them once the end of the input is reached
+func (loc *locator) holdContext(class string, line uint64) {
+	if loc.context != nil && loc.wanted[class] {
+		loc.context.hold(line)
+	}
+}
+
+// contextLines - the lines surrounding the recorded locations, or nil without --context
+func (loc *locator) contextLines() []ContextLine {
+	if loc.context == nil {
+		return nil
+	}
+	return loc.context.finish()
+}
+
+// recordBom - a byte order mark is always at the very start of the file
+func (loc *locator) recordBom(bom string) {
+	for _, class := range loc.bomClasses {
+		if bomMatchesClass(bom, class) {
+			loc.wanted["bom"] = true
+			loc.record("bom", Location{Line: 1, Column: 1})
+			return
+		}
+	}
+}
+
+// OutputLocations - display each location compiler-style, such as: file.txt:12:7: crlf
+// with --context, the surrounding lines follow their locations
+func OutputLocations(allStats []SpecialChars) error {
+	w := bufio.NewWriter(os.Stdout)
+	color := isTerminal(os.Stdout)
+	for _, s := range allStats {
+		if len(s.Context) > 0 {
+			outputContext(w, s, color)
+		} else {
+			for _, at := range s.Locations {
+				_, _ = fmt.Fprintf(w, "%s:%d:%d: %s\n", s.Filename, at.Line, at.Column, at.Class)
+			}
+		}
+		if s.LocationsOmitted > 0 {
+			_, _ = fmt.Fprintf(w, "%s: %d more locations not shown, see --max-locations\n", s.Filename, s.LocationsOmitted)
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,26 @@
+package chars
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+// TestMaxlineLocations - -f maxline locates each line wider than --max-line as longlines
+func TestMaxlineLocations(t *testing.T) {
+	input := "short\n" + strings.Repeat("x", 12) + "\nok\n\t\tabc\n"
+	opts := Options{Locations: true, LocationClasses: []string{"maxline"}, MaxLine: 10}
+	stats, cerr := searchForSpecialChars("long.txt", bufio.NewReader(strings.NewReader(input)), opts)
+	if cerr.code != 0 {
+		t.Fatal(cerr.err)
+	}
+	want := []Location{{Line: 2, Column: 11, Offset: 16, Class: "longlines"}, {Line: 4, Column: 2, Offset: 23, Class: "longlines"}}
+	if len(stats.Locations) != len(want) {
+		t.Fatalf("found %+v, want %+v", stats.Locations, want)
+	}
+	for i := range want {
+		if stats.Locations[i] != want[i] {
+			t.Errorf("location %d = %+v, want %+v", i, stats.Locations[i], want[i])
+		}
+	}
+}
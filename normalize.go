@@ -0,0 +1,206 @@
+package chars
+
+/*
+normalize.go
+
+Check whether text is in Unicode Normalization Form C, as well as NFKC, and rewrite files in
+either form with --normalize. Files written on macOS often contain decomposed characters, such
+as e followed by U+0301 COMBINING ACUTE ACCENT instead of é, which look the same but do not
+compare as equal. Text is examined one segment at a time: a character which does not combine
+with anything before it, followed by the characters which may combine with it.
+*/
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// forms accepted by --normalize
+const (
+	NormNfc  string = "nfc"
+	NormNfkc string = "nfkc"
+)
+
+// ValidNormalForm - return true if form can be used with --normalize
+func ValidNormalForm(form string) bool {
+	return form == NormNfc || form == NormNfkc
+}
+
+// normalForm - the norm.Form with the given name
+func normalForm(name string) norm.Form {
+	if name == NormNfkc {
+		return norm.NFKC
+	}
+	return norm.NFC
+}
+
+// normChecker - counts the characters and lines which are not in a normalization form
+type normChecker struct {
+	form        norm.Form
+	class       string // locations are recorded with this class, unless it is empty
+	segment     []byte
+	ascii       bool // the segment is the single ASCII character last, which is always normal
+	last        byte
+	start       Location // where the segment starts, unless it is a single ASCII character
+	single      bool     // the segment is one non-ASCII character, so whether it is normal is already known
+	normal      bool
+	changed     bool // the current line has a segment which is not normal
+	codePoints  uint64
+	lines       uint64
+	known       map[rune]normInfo // each non-ASCII character already looked up
+	encodedRune [utf8.UTFMax]byte
+}
+
+// normInfo - whether a character can start a segment, and whether it is normal on its own
+type normInfo struct {
+	boundaryBefore bool
+	normal         bool
+}
+
+func newNormChecker(form norm.Form, class string) normChecker {
+	return normChecker{form: form, class: class, known: make(map[rune]normInfo)}
+}
+
+// skip - return true when r does not need to be examined with add: an ASCII character after another
+// ASCII character can not be part of a segment which is not normal
+func (nc *normChecker) skip(r rune) bool {
+	if r < utf8.RuneSelf && nc.ascii && !nc.changed {
+		nc.last = byte(r)
+		return true
+	}
+	return false
+}
+
+// addRun - examine a run of ASCII characters; only the first of them can end a segment which is not normal
+func (nc *normChecker) addRun(loc *locator, run []byte) {
+	if !nc.skip(rune(run[0])) {
+		nc.add(loc, rune(run[0]), Location{})
+	}
+	nc.last = run[len(run)-1]
+}
+
+// add - examine the character r, which starts at position at; a line ending also ends the current line
+func (nc *normChecker) add(loc *locator, r rune, at Location) {
+	if r < utf8.RuneSelf {
+		if !nc.ascii {
+			nc.flush(loc)
+		}
+		if r == '\n' || r == '\r' {
+			nc.endLine()
+		}
+		nc.ascii, nc.last = true, byte(r)
+		return
+	}
+
+	info, ok := nc.known[r]
+	if !ok {
+		encoded := utf8.AppendRune(nc.encodedRune[:0], r)
+		info = normInfo{boundaryBefore: nc.form.Properties(encoded).BoundaryBefore(), normal: nc.form.IsNormal(encoded)}
+		nc.known[r] = info
+	}
+	switch {
+	case info.boundaryBefore || len(nc.segment) >= norm.MaxSegmentSize:
+		if !nc.ascii {
+			nc.flush(loc)
+		}
+		nc.segment, nc.start = nc.segment[:0], at
+		nc.single, nc.normal = true, info.normal
+	case nc.ascii:
+		// such as e followed by a combining accent; the segment starts at the ASCII character before r
+		nc.segment, nc.start = append(nc.segment[:0], nc.last), at
+		if nc.last != '\n' && nc.last != '\r' {
+			nc.start.Column--
+			nc.start.Offset--
+		}
+		nc.single = false
+	default:
+		nc.single = false
+	}
+	nc.segment = utf8.AppendRune(nc.segment, r)
+	nc.ascii = false
+}
+
+// flush - check the current segment, which is complete
+func (nc *normChecker) flush(loc *locator) {
+	if len(nc.segment) == 0 || nc.single && nc.normal || !nc.single && nc.form.IsNormal(nc.segment) {
+		nc.segment = nc.segment[:0]
+		return
+	}
+	nc.codePoints += uint64(utf8.RuneCount(nc.segment))
+	nc.segment = nc.segment[:0]
+	nc.changed = true
+	if nc.class != "" {
+		loc.record(nc.class, nc.start)
+	}
+}
+
+func (nc *normChecker) endLine() {
+	if nc.changed {
+		nc.lines++
+	}
+	nc.changed = false
+}
+
+// finish - the end of the input was reached
+func (nc *normChecker) finish(loc *locator) {
+	if !nc.ascii {
+		nc.flush(loc)
+	}
+	nc.endLine()
+}
+
+// normFixer - rewrite each segment which is not in the normalization form; invalid UTF-8 is copied as-is
+type normFixer struct {
+	to    string
+	form  norm.Form
+	count uint64
+}
+
+func newNormFixer(to string) *normFixer {
+	return &normFixer{to: to, form: normalForm(to)}
+}
+
+func (nf *normFixer) name() string  { return nf.to }
+func (nf *normFixer) edits() uint64 { return nf.count }
+func (nf *normFixer) Reset()        { nf.count = 0 }
+
+// Transform - a segment at the end of src is held back until it is known what follows it
+func (nf *normFixer) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for nSrc < len(src) {
+		end := nf.form.NextBoundary(src[nSrc:], atEOF)
+		if end < 0 {
+			if len(src)-nSrc < norm.MaxSegmentSize {
+				return nDst, nSrc, transform.ErrShortSrc
+			}
+			// more text will not help, such as when the segment contains invalid UTF-8
+			end = max(1, validPrefix(src[nSrc:nSrc+norm.MaxSegmentSize]))
+		}
+
+		segment := src[nSrc : nSrc+end]
+		if !nf.form.IsNormal(segment) {
+			segment = nf.form.Bytes(segment)
+			nf.count++
+		}
+		if len(dst)-nDst < len(segment) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		nDst += copy(dst[nDst:], segment)
+		nSrc += end
+	}
+	return nDst, nSrc, nil
+}
+
+// validPrefix - the length of the valid UTF-8 at the start of b
+func validPrefix(b []byte) int {
+	n := 0
+	for n < len(b) {
+		r, size := utf8.DecodeRune(b[n:])
+		if r == utf8.RuneError && size <= 1 {
+			break
+		}
+		n += size
+	}
+	return n
+}
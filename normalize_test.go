@@ -0,0 +1,46 @@
+package chars
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+// TestNfcLegacyEncoding - text which is not UTF-8 is not reported as needing normalization, even when the
+// invalid bytes follow text which does
+func TestNfcLegacyEncoding(t *testing.T) {
+	for _, input := range []string{"cafe\u0301\n\x93\xfa\x96\x7b\n", "\x93\xfa\x96\x7b\ncafe\u0301\n"} {
+		opts := Options{Locations: true, LocationClasses: []string{"nfc"}, Context: true, ContextLines: 1, Nfkc: true}
+		stats, cerr := searchForSpecialChars("sjis.txt", bufio.NewReader(strings.NewReader(input)), opts)
+		if cerr.code != 0 {
+			t.Fatal(cerr.err)
+		}
+		if !stats.Nfc || stats.NfcCodePoints != 0 || stats.NfcLines != 0 || stats.NfkcCodePoints != 0 || stats.NfkcLines != 0 {
+			t.Errorf("%q: nfc %v with %d code points on %d lines, nfkc %d code points on %d lines, want none", input,
+				stats.Nfc, stats.NfcCodePoints, stats.NfcLines, stats.NfkcCodePoints, stats.NfkcLines)
+		}
+		if len(stats.Locations) != 0 || len(stats.Context) != 0 {
+			t.Errorf("%q: found %+v with context %+v, want none", input, stats.Locations, stats.Context)
+		}
+	}
+}
+
+func TestNormFixer(t *testing.T) {
+	tests := []struct {
+		to, input, want string
+		edits           uint64
+	}{
+		{NormNfc, "cafe\u0301\n", "caf\u00e9\n", 1},
+		{NormNfc, "caf\u00e9\n", "caf\u00e9\n", 0},
+		// each segment which changes is one edit: e with two accents, and the Angstrom sign
+		{NormNfc, "e\u0302\u0301 \u212b\n", "\u1ebf \u00c5\n", 2},
+		{NormNfc, "\ufb01le\u00a0x\n", "\ufb01le\u00a0x\n", 0},
+		{NormNfkc, "\ufb01le\u00a0x\n", "file x\n", 2},
+		// invalid UTF-8, such as text in a legacy encoding, is copied as-is
+		{NormNfc, "a\xff\xfeb\x93\xfa\n", "a\xff\xfeb\x93\xfa\n", 0},
+		{NormNfc, "", "", 0},
+	}
+	for _, tt := range tests {
+		checkStage(t, newNormFixer(tt.to), tt.input, tt.want, tt.edits)
+	}
+}
@@ -0,0 +1,166 @@
+package chars
+
+/*
+runes.go
+
+Examine each character decoded from UTF-8 input by searchForSpecialChars, for characters
+which are dangerous even though they are valid, such as the bidirectional controls used by
+Trojan Source attacks, invisible characters pasted from chat and word processors, and
+characters which look like ASCII
+https://trojansource.codes/
+*/
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"unicode"
+
+	"golang.org/x/text/unicode/runenames"
+)
+
+// ConfusableChar - a non-ASCII character found with --confusables which looks like the Ascii character;
+// First is where it was first found
+type ConfusableChar struct {
+	CodePoint string   `json:"codePoint"`
+	Name      string   `json:"name"`
+	Ascii     string   `json:"ascii"`
+	Count     uint64   `json:"count"`
+	First     Location `json:"first"`
+}
+
+// defaultIgnorable - the Default_Ignorable_Code_Point property from DerivedCoreProperties.txt,
+// characters which are not displayed at all; the unicode package only has part of it
+// https://www.unicode.org/Public/UCD/latest/ucd/DerivedCoreProperties.txt
+var defaultIgnorable = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x00ad, Hi: 0x00ad, Stride: 1}, // soft hyphen
+		{Lo: 0x034f, Hi: 0x034f, Stride: 1}, // combining grapheme joiner
+		{Lo: 0x061c, Hi: 0x061c, Stride: 1},
+		{Lo: 0x115f, Hi: 0x1160, Stride: 1}, // Hangul fillers
+		{Lo: 0x17b4, Hi: 0x17b5, Stride: 1},
+		{Lo: 0x180b, Hi: 0x180f, Stride: 1}, // Mongolian variation selectors
+		{Lo: 0x200b, Hi: 0x200f, Stride: 1}, // zero width space, non-joiner, joiner
+		{Lo: 0x202a, Hi: 0x202e, Stride: 1},
+		{Lo: 0x2060, Hi: 0x206f, Stride: 1}, // word joiner, invisible operators
+		{Lo: 0x3164, Hi: 0x3164, Stride: 1},
+		{Lo: 0xfe00, Hi: 0xfe0f, Stride: 1}, // variation selectors
+		{Lo: 0xfeff, Hi: 0xfeff, Stride: 1}, // zero width no-break space, or a BOM
+		{Lo: 0xffa0, Hi: 0xffa0, Stride: 1},
+		{Lo: 0xfff0, Hi: 0xfff8, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1bca0, Hi: 0x1bca3, Stride: 1},
+		{Lo: 0x1d173, Hi: 0x1d17a, Stride: 1},
+		{Lo: 0xe0000, Hi: 0xe0fff, Stride: 1}, // tags and variation selectors supplement
+	},
+}
+
+// isBidiControl - return true for the explicit directional embeddings, overrides and isolates,
+// the implicit directional marks and the Arabic letter mark, see CVE-2021-42574
+func isBidiControl(r rune) bool {
+	switch {
+	case r >= 0x202a && r <= 0x202e: // LRE, RLE, PDF, LRO, RLO
+		return true
+	case r >= 0x2066 && r <= 0x2069: // LRI, RLI, FSI, PDI
+		return true
+	case r == 0x200e || r == 0x200f || r == 0x061c: // LRM, RLM, ALM
+		return true
+	}
+	return false
+}
+
+// isInvisible - return true for a default ignorable character which is not a bidirectional control,
+// since those are counted separately; a BOM is only invisible when it is not at the start of the file
+func isInvisible(r rune, offset uint64) bool {
+	if r == 0xfeff && offset == 0 || isBidiControl(r) {
+		return false
+	}
+	return unicode.Is(defaultIgnorable, r)
+}
+
+// runeTracker - counts for characters of interest; at is the location of the first byte of each character
+type runeTracker struct {
+	bidi      uint64
+	invisible uint64
+	c1        uint64 // U+0080 to U+009F
+
+	// characters which look like ASCII are always counted; with --confusables, each one is also
+	// listed in the order it was first found
+	confusable      uint64
+	findConfusables bool
+	confusables     []ConfusableChar
+	seen            map[rune]int // index into confusables
+
+	counts map[rune]uint64 // with --histogram, how many times each character was found
+}
+
+func newRuneTracker(opts Options) runeTracker {
+	rt := runeTracker{findConfusables: opts.Confusables}
+	if opts.Histogram {
+		rt.counts = make(map[rune]uint64)
+	}
+	return rt
+}
+
+// add - examine a character which has been completely decoded; offset is the position of its first byte
+// in the examined text, which is known even when locations are not tracked
+func (rt *runeTracker) add(loc *locator, r rune, at Location, offset uint64) {
+	if rt.counts != nil {
+		rt.counts[r]++
+	}
+	switch {
+	case r <= 0x9f:
+		rt.c1++
+		loc.record("c1", at)
+	case isBidiControl(r):
+		rt.bidi++
+		loc.record("bidi", at)
+	case isInvisible(r, offset):
+		rt.invisible++
+		loc.record("invisible", at)
+	default:
+		rt.addConfusable(loc, r, at)
+	}
+}
+
+// addConfusable - count r when it can be mistaken for an ASCII character
+func (rt *runeTracker) addConfusable(loc *locator, r rune, at Location) {
+	ascii, ok := confusableAscii[r]
+	if !ok {
+		return
+	}
+	rt.confusable++
+	loc.record("confusable", at)
+	if !rt.findConfusables {
+		return
+	}
+	if i, ok := rt.seen[r]; ok {
+		rt.confusables[i].Count++
+		return
+	}
+	if rt.seen == nil {
+		rt.seen = make(map[rune]int)
+	}
+	rt.seen[r] = len(rt.confusables)
+	at.Class = "confusable"
+	rt.confusables = append(rt.confusables, ConfusableChar{CodePoint: fmt.Sprintf("U+%04X", r),
+		Name: runenames.Name(r), Ascii: string(ascii), Count: 1, First: at})
+}
+
+// OutputConfusables - display each character found with --confusables, compiler-style at the location
+// where it was first found, along with the ASCII character it looks like
+func OutputConfusables(allStats []SpecialChars) error {
+	w := bufio.NewWriter(os.Stdout)
+	for _, s := range allStats {
+		for _, c := range s.Confusables {
+			times := "times"
+			if c.Count == 1 {
+				times = "time"
+			}
+			_, _ = fmt.Fprintf(w, "%s:%d:%d: %s %s looks like %q, found %d %s\n", s.Filename,
+				c.First.Line, c.First.Column, c.CodePoint, c.Name, c.Ascii, c.Count, times)
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,34 @@
+package chars
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+// TestConfusablesWithoutLocations - where each confusable was first found is reported with --confusables
+// alone, even though no locations are recorded
+func TestConfusablesWithoutLocations(t *testing.T) {
+	input := "x\np\u0430y \u0430\n\t\uff41\n"
+	stats, cerr := searchForSpecialChars("c.txt", bufio.NewReader(strings.NewReader(input)), Options{Confusables: true})
+	if cerr.code != 0 {
+		t.Fatal(cerr.err)
+	}
+	if len(stats.Locations) != 0 {
+		t.Errorf("found %d locations, want none", len(stats.Locations))
+	}
+	want := []ConfusableChar{
+		{CodePoint: "U+0430", Name: "CYRILLIC SMALL LETTER A", Ascii: "a", Count: 2,
+			First: Location{Line: 2, Column: 2, Offset: 3, Class: "confusable"}},
+		{CodePoint: "U+FF41", Name: "FULLWIDTH LATIN SMALL LETTER A", Ascii: "a", Count: 1,
+			First: Location{Line: 3, Column: 2, Offset: 11, Class: "confusable"}},
+	}
+	if len(stats.Confusables) != len(want) {
+		t.Fatalf("found %+v, want %+v", stats.Confusables, want)
+	}
+	for i := range want {
+		if stats.Confusables[i] != want[i] {
+			t.Errorf("confusable %d = %+v, want %+v", i, stats.Confusables[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package chars
+
+/*
+scripts.go
+
+Count the characters of each Unicode script for --scripts, such as Latin, Cyrillic or Han, along
+with the non-ASCII characters of each Unicode block, such as Latin-1 Supplement, and find words
+which mix scripts, such as Latin letters next to a Cyrillic letter. Characters which
+are shared by many scripts, such as digits and punctuation, belong to the Common and Inherited
+scripts and are not counted.
+*/
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"unicode"
+
+	"github.com/jftuga/ellipsis"
+	"github.com/olekukonko/tablewriter"
+)
+
+// allowedScriptMixes - scripts which are written together within a single word, following the
+// Highly Restrictive level of Unicode Technical Standard #39
+var allowedScriptMixes = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+// scriptNames - the names of unicode.Scripts in a fixed order, so that lookups are repeatable
+var scriptNames []string
+
+func init() {
+	for name := range unicode.Scripts {
+		scriptNames = append(scriptNames, name)
+	}
+	sort.Strings(scriptNames)
+}
+
+// scriptTracker - words which mix scripts are always found, while the characters of each script are
+// only counted with --scripts
+type scriptTracker struct {
+	counts map[string]uint64
+	blocks map[string]uint64
+	cache  map[rune]string // script of each non-ASCII character already looked up
+
+	word        []string // scripts of the current word
+	wordState   uint8
+	wordStart   Location
+	mixedScript uint64
+}
+
+func newScriptTracker(opts Options) scriptTracker {
+	st := scriptTracker{cache: make(map[rune]string)}
+	if opts.Scripts {
+		st.counts, st.blocks = make(map[string]uint64), make(map[string]uint64)
+	}
+	return st
+}
+
+// scriptOf - the script of the non-ASCII character r; Common or Inherited for characters used with more than one script
+func (st *scriptTracker) scriptOf(r rune) string {
+	if script, ok := st.cache[r]; ok {
+		return script
+	}
+	script := "Common"
+	for _, name := range scriptNames {
+		if unicode.Is(unicode.Scripts[name], r) {
+			script = name
+			break
+		}
+	}
+	st.cache[r] = script
+	return script
+}
+
+// the current word as far as addAscii is concerned
+const (
+	wordNone  uint8 = iota // between words
+	wordLatin              // within a word containing Latin, when characters are not counted for --scripts
+	wordOther              // within any other word
+)
+
+// asciiWordChar - wordLatin for the ASCII characters which are part of a word, otherwise wordNone
+var asciiWordChar = func() (table [256]uint8) {
+	for _, r := range "0123456789_abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ" {
+		table[r] = wordLatin
+	}
+	return table
+}()
+
+// addAscii - examine the ASCII character r, which starts at loc.pos; most characters continue a Latin word
+// or are found between words, which is checked first so that this is inlined
+func (st *scriptTracker) addAscii(loc *locator, r rune) {
+	if asciiWordChar[byte(r)] != st.wordState {
+		st.asciiWord(loc, r, loc.pos)
+	}
+}
+
+// addRun - examine the run of ASCII characters starting at position at; unless characters are counted for
+// --scripts, only the words at either end of the run are examined, since a word which only has ASCII
+// letters can not mix scripts
+func (st *scriptTracker) addRun(loc *locator, run []byte, at Location) {
+	last := len(run) - 1
+	for last >= 0 && asciiWordChar[run[last]] != wordNone {
+		last--
+	}
+	if st.counts != nil || last < 0 {
+		st.addChars(loc, run, at)
+		return
+	}
+	if st.wordState != wordNone {
+		first := 0
+		for asciiWordChar[run[first]] != wordNone {
+			first++
+		}
+		st.addChars(loc, run[:first+1], at) // the end of the word in progress
+	}
+	// there is no word in progress after the character at last, which is not part of a word
+	next := uint64(last + 1)
+	st.addChars(loc, run[next:], at.advance(next))
+}
+
+// addChars - examine each of the ASCII characters in chars, starting at position at
+func (st *scriptTracker) addChars(loc *locator, chars []byte, at Location) {
+	for i, b := range chars {
+		if asciiWordChar[b] != st.wordState {
+			st.asciiWord(loc, rune(b), at.advance(uint64(i)))
+		}
+	}
+}
+
+// asciiWord - the ASCII character r at position at starts or ends a word, or is counted with --scripts
+func (st *scriptTracker) asciiWord(loc *locator, r rune, at Location) {
+	switch {
+	case asciiWordChar[byte(r)] == wordNone:
+		if len(st.word) > 0 {
+			st.endWord(loc)
+		}
+	case r|0x20 >= 'a' && r|0x20 <= 'z':
+		st.addScript("Latin", at)
+	}
+}
+
+// blockOf - the name of the Unicode block containing r, or No_Block
+func blockOf(r rune) string {
+	i := sort.Search(len(unicodeBlocks), func(i int) bool { return unicodeBlocks[i].hi >= r })
+	if i < len(unicodeBlocks) && unicodeBlocks[i].lo <= r {
+		return unicodeBlocks[i].name
+	}
+	return "No_Block"
+}
+
+// add - examine the non-ASCII character r, which starts at position at; words are runs of letters, marks,
+// digits and underscores
+func (st *scriptTracker) add(loc *locator, r rune, at Location) {
+	if st.blocks != nil {
+		st.blocks[blockOf(r)]++
+	}
+	if !unicode.IsLetter(r) && !unicode.IsMark(r) && !unicode.IsDigit(r) {
+		st.endWord(loc)
+		return
+	}
+	if script := st.scriptOf(r); script != "Common" && script != "Inherited" {
+		st.addScript(script, at)
+	}
+}
+
+// addScript - a character of script was found within a word
+func (st *scriptTracker) addScript(script string, at Location) {
+	if st.counts != nil {
+		st.counts[script]++
+	}
+	if len(st.word) == 0 {
+		st.wordStart = at
+	} else if slices.Contains(st.word, script) {
+		return
+	}
+	st.word = append(st.word, script)
+	if st.wordState != wordLatin {
+		st.wordState = wordOther
+		if script == "Latin" && st.counts == nil {
+			st.wordState = wordLatin
+		}
+	}
+}
+
+// endWord - a character which is not part of a word, or the end of the input, was found
+func (st *scriptTracker) endWord(loc *locator) {
+	if len(st.word) > 1 && !allowedScriptMix(st.word) {
+		st.mixedScript++
+		loc.record("mixedscript", st.wordStart)
+	}
+	st.word, st.wordState = st.word[:0], wordNone
+}
+
+// allowedScriptMix - return true when all of the scripts can be written together in one word
+func allowedScriptMix(scripts []string) bool {
+	for _, allowed := range allowedScriptMixes {
+		all := true
+		for _, script := range scripts {
+			all = all && slices.Contains(allowed, script)
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+// finish - the end of the input was reached
+func (st *scriptTracker) finish(loc *locator) {
+	st.endWord(loc)
+}
+
+// OutputScripts - display a text table with the number of characters of each script in each file,
+// followed by one with the number of non-ASCII characters of each block, from the most to the least common
+func OutputScripts(allStats []SpecialChars, maxLength int, wantCommas bool) error {
+	if len(allStats) == 0 {
+		return nil
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	outputCountTable(w, allStats, "script", func(s SpecialChars) map[string]uint64 { return s.Scripts }, maxLength, wantCommas)
+	outputCountTable(w, allStats, "block", func(s SpecialChars) map[string]uint64 { return s.Blocks }, maxLength, wantCommas)
+	return w.Flush()
+}
+
+// outputCountTable - render a table with one row for each name in the counts of each file, with its percentage of the file's total
+func outputCountTable(w io.Writer, allStats []SpecialChars, column string, counts func(SpecialChars) map[string]uint64, maxLength int, wantCommas bool) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"filename", column, "chars", "percent"})
+	var name string
+	for _, s := range allStats {
+		if maxLength == 0 {
+			name = s.Filename
+		} else {
+			name = ellipsis.Shorten(s.Filename, maxLength)
+		}
+		found := counts(s)
+		var total uint64
+		names := make([]string, 0, len(found))
+		for n, count := range found {
+			names = append(names, n)
+			total += count
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if found[names[i]] != found[names[j]] {
+				return found[names[i]] > found[names[j]]
+			}
+			return names[i] < names[j]
+		})
+		for _, n := range names {
+			count := found[n]
+			table.Append([]string{name, n, formatCount(count, wantCommas),
+				fmt.Sprintf("%.1f%%", float64(count)*100/float64(total))})
+		}
+	}
+	table.Render()
+}
@@ -0,0 +1,223 @@
+package chars
+
+/*
+suggest.go
+
+Display a unified diff between each file and its normal form for --suggest: LF line endings,
+no BOM, no trailing whitespace and a final newline. Every change made by the normal form stays
+within a line, so each line of the file is compared with what it becomes instead of searching
+for the shortest edit script. Invisible characters are made visible, the same as --context,
+so that a line which only changes from CRLF to LF does not look identical.
+*/
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/text/transform"
+)
+
+// unchanged lines displayed before and after each change
+const suggestContext int = 3
+
+// ANSI escape sequences for removed and added lines
+const (
+	ansiRemoved string = "\x1b[31m"
+	ansiAdded   string = "\x1b[32m"
+)
+
+// diffLine - kind is ' ' for an unchanged line, '-' for a removed line or '+' for an added line
+type diffLine struct {
+	kind byte
+	text []byte
+}
+
+// diffHunk - oldStart and newStart are the line numbers of the first line in each file
+type diffHunk struct {
+	oldStart, oldCount int
+	newStart, newCount int
+	lines              []diffLine
+}
+
+// normalizer - the fix stages which produce the normal form of a single line
+type normalizer struct {
+	first transform.Transformer // also removes the BOM
+	other transform.Transformer
+}
+
+func newNormalizer() normalizer {
+	return normalizer{
+		first: transform.Chain(newEolFixer(EolLf), &trailingStripper{}, &bomFixer{}),
+		other: transform.Chain(newEolFixer(EolLf), &trailingStripper{}),
+	}
+}
+
+// normalize - return the normal form of line, which ends with LF unless it is the last line of the file;
+// a line containing a lone CR becomes more than one line
+func (nz normalizer) normalize(line []byte, first, last bool) ([]byte, error) {
+	t := nz.other
+	if first {
+		t = nz.first
+	}
+	out, _, err := transform.Bytes(t, line)
+	if err != nil {
+		return nil, err
+	}
+	if last && len(out) > 0 && out[len(out)-1] != '\n' {
+		out = append(out, '\n')
+	}
+	return out, nil
+}
+
+// splitLines - split after each LF; the last line may not end with one
+func splitLines(text []byte) [][]byte {
+	var lines [][]byte
+	for len(text) > 0 {
+		end := bytes.IndexByte(text, '\n') + 1
+		if end == 0 {
+			end = len(text)
+		}
+		lines = append(lines, text[:end])
+		text = text[end:]
+	}
+	return lines
+}
+
+// suggestFile - return the hunks which turn a file that has already been examined into its normal form;
+// binary, UTF-16 and UTF-32 files are not compared
+func suggestFile(filename string, stats SpecialChars) ([]diffHunk, error) {
+	text, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	firstBlock := text[:min(len(text), BlockSize)]
+	if fixSkipReason(stats, firstBlock, nil) != "" {
+		return nil, nil
+	}
+
+	nz := newNormalizer()
+	lines := splitLines(text)
+	var diff []diffLine
+	changed := false
+	for i, line := range lines {
+		normal, err := nz.normalize(line, i == 0, i == len(lines)-1)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(line, normal) {
+			diff = append(diff, diffLine{' ', line})
+			continue
+		}
+		changed = true
+		diff = append(diff, diffLine{'-', line})
+		for _, n := range splitLines(normal) {
+			diff = append(diff, diffLine{'+', n})
+		}
+	}
+	if !changed {
+		return nil, nil
+	}
+	return groupHunks(diff), nil
+}
+
+// groupHunks - keep suggestContext unchanged lines around each change, joining changes which are close together
+func groupHunks(diff []diffLine) []diffHunk {
+	var hunks []diffHunk
+	oldLine, newLine := 1, 1 // line numbers of diff[i]
+	for i := 0; i < len(diff); {
+		if diff[i].kind == ' ' {
+			oldLine++
+			newLine++
+			i++
+			continue
+		}
+
+		start := max(0, i-suggestContext)
+		hunk := diffHunk{oldStart: oldLine - (i - start), newStart: newLine - (i - start)}
+		end := i
+		for unchanged := 0; end < len(diff) && unchanged <= 2*suggestContext; end++ {
+			if diff[end].kind == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+		}
+		// end is past the trailing unchanged lines; keep only suggestContext of them
+		for end > i && diff[end-1].kind == ' ' {
+			end--
+		}
+		end = min(len(diff), end+suggestContext)
+
+		hunk.lines = diff[start:end]
+		for _, line := range diff[i:end] {
+			if line.kind != '+' {
+				oldLine++
+			}
+			if line.kind != '-' {
+				newLine++
+			}
+		}
+		for _, line := range hunk.lines {
+			if line.kind != '+' {
+				hunk.oldCount++
+			}
+			if line.kind != '-' {
+				hunk.newCount++
+			}
+		}
+		// an empty range starts at the line before it, as with diff -u
+		if hunk.oldCount == 0 {
+			hunk.oldStart--
+		}
+		if hunk.newCount == 0 {
+			hunk.newStart--
+		}
+		hunks = append(hunks, hunk)
+		i = end
+	}
+	return hunks
+}
+
+// writeDiff - display the hunks of a file in unified diff format, with invisible characters made visible
+func writeDiff(w io.Writer, filename string, hunks []diffHunk, color bool) {
+	_, _ = fmt.Fprintf(w, "--- a/%s\n+++ b/%s\n", filename, filename)
+	for _, hunk := range hunks {
+		_, _ = fmt.Fprintf(w, "@@ -%d,%d +%d,%d @@\n", hunk.oldStart, hunk.oldCount, hunk.newStart, hunk.newCount)
+		for _, line := range hunk.lines {
+			kind := string(line.kind)
+			if color && line.kind == '-' {
+				kind = ansiRemoved + kind + ansiReset
+			} else if color && line.kind == '+' {
+				kind = ansiAdded + kind + ansiReset
+			}
+			_, _ = fmt.Fprintf(w, "%s%s\n", kind, renderVisible(ContextLine{raw: line.text}, color))
+		}
+	}
+}
+
+// renderDiff - the diff of a file without color, for JSON output
+func renderDiff(filename string, hunks []diffHunk) string {
+	var sb strings.Builder
+	writeDiff(&sb, filename, hunks, false)
+	return sb.String()
+}
+
+// OutputSuggestions - display the diff of each file which is not in its normal form; returns the
+// number of files which would change
+func OutputSuggestions(allStats []SpecialChars) (uint64, error) {
+	var changed uint64
+	w := bufio.NewWriter(os.Stdout)
+	color := isTerminal(os.Stdout)
+	for _, s := range allStats {
+		if len(s.hunks) == 0 {
+			continue
+		}
+		changed++
+		writeDiff(w, s.Filename, s.hunks, color)
+	}
+	return changed, w.Flush()
+}
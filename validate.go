@@ -0,0 +1,112 @@
+package chars
+
+/*
+validate.go
+
+Streaming UTF-8 validation which carries partial sequences across block boundaries
+https://en.wikipedia.org/wiki/UTF-8#Invalid_sequences_and_error_handling
+*/
+
+import (
+	"unicode/utf8"
+)
+
+// utf8Validator - a sequence split across two reads is still validated as a single character
+type utf8Validator struct {
+	need      int    // continuation bytes still expected for the current sequence
+	size      int    // total length of the current sequence
+	cp        rune   // code point accumulated so far
+	start     uint64 // offset of the lead byte of the current sequence
+	multibyte uint64 // valid code points encoded with more than one byte
+	invalid   uint64 // stray, truncated, out of range or undefined bytes
+	overlong  uint64 // code points encoded with more bytes than necessary
+	surrogate uint64 // UTF-16 surrogate halves encoded as UTF-8
+	first     int64  // offset of the first invalid byte or -1
+	track     bool   // keep the offset of each invalid sequence in fails
+	fails     []uint64
+}
+
+func newUtf8Validator() utf8Validator {
+	return utf8Validator{first: -1}
+}
+
+// fail - record an invalid sequence starting at offset
+func (v *utf8Validator) fail(counter *uint64, offset uint64) {
+	*counter++
+	if v.first < 0 {
+		v.first = int64(offset)
+	}
+	if v.track {
+		v.fails = append(v.fails, offset)
+	}
+	v.need = 0
+}
+
+// feed - examine the byte at offset; returns the decoded character and true once a valid
+// character is complete, along with the number of sequences which this byte showed to be invalid
+func (v *utf8Validator) feed(b byte, offset uint64) (rune, bool, uint64) {
+	var failed uint64
+	if v.need > 0 {
+		if b >= 0x80 && b < 0xc0 {
+			v.cp = v.cp<<6 | rune(b&0x3f)
+			v.need--
+			if v.need > 0 {
+				return 0, false, 0
+			}
+			return v.complete()
+		}
+		// the sequence was cut short by a byte which is not a continuation byte
+		v.fail(&v.invalid, v.start)
+		failed++
+	}
+
+	switch {
+	case b < 0x80:
+		return rune(b), true, failed
+	case b < 0xc0:
+		v.fail(&v.invalid, offset) // continuation byte without a lead byte
+		return 0, false, failed + 1
+	case b < 0xe0:
+		v.begin(2, rune(b&0x1f), offset)
+	case b < 0xf0:
+		v.begin(3, rune(b&0x0f), offset)
+	case b < 0xf8:
+		v.begin(4, rune(b&0x07), offset)
+	default:
+		v.fail(&v.invalid, offset) // 0xF8 - 0xFF never appear in UTF-8
+		failed++
+	}
+	return 0, false, failed
+}
+
+// begin - start a new multibyte sequence
+func (v *utf8Validator) begin(size int, cp rune, offset uint64) {
+	v.size = size
+	v.need = size - 1
+	v.cp = cp
+	v.start = offset
+}
+
+// complete - classify a structurally complete multibyte sequence
+func (v *utf8Validator) complete() (rune, bool, uint64) {
+	minimum := [...]rune{0, 0, 0x80, 0x800, 0x10000}
+	switch {
+	case v.cp < minimum[v.size]:
+		v.fail(&v.overlong, v.start)
+	case v.cp >= 0xd800 && v.cp <= 0xdfff:
+		v.fail(&v.surrogate, v.start)
+	case v.cp > utf8.MaxRune:
+		v.fail(&v.invalid, v.start)
+	default:
+		v.multibyte++
+		return v.cp, true, 0
+	}
+	return 0, false, 1
+}
+
+// finish - a sequence still in progress at the end of the input is truncated
+func (v *utf8Validator) finish() {
+	if v.need > 0 {
+		v.fail(&v.invalid, v.start)
+	}
+}
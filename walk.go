@@ -0,0 +1,257 @@
+package chars
+
+/*
+walk.go
+
+Recursively expand directories given on the command line with -r, using the same rules on
+every OS: include and exclude globs, a maximum depth, hidden files and following symbolic
+links without getting caught in a loop
+*/
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// globToRegexp - convert a glob into an anchored regular expression; * and ? do not match /,
+// ** matches any number of directories and [...] is a character class
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	var re strings.Builder
+	re.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if strings.HasPrefix(glob[i:], "**/") {
+				re.WriteString("(?:.*/)?")
+				i += 2
+			} else if strings.HasPrefix(glob[i:], "**") {
+				re.WriteString(".*")
+				i++
+			} else {
+				re.WriteString("[^/]*")
+			}
+		case '?':
+			re.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated character class in glob: %s", glob)
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			re.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				re.WriteString(regexp.QuoteMeta(string(glob[i])))
+			}
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	re.WriteString("$")
+	return regexp.Compile(re.String())
+}
+
+// globList - globs containing a / are matched against the path relative to the directory being
+// walked, all others are matched against the file name only
+type globList struct {
+	byName []*regexp.Regexp
+	byPath []*regexp.Regexp
+}
+
+func newGlobList(globs []string) (globList, error) {
+	var list globList
+	for _, glob := range globs {
+		glob = filepath.ToSlash(glob)
+		anchored := strings.Contains(strings.TrimSuffix(glob, "/"), "/")
+		re, err := globToRegexp(strings.Trim(glob, "/"))
+		if err != nil {
+			return list, err
+		}
+		if anchored {
+			list.byPath = append(list.byPath, re)
+		} else {
+			list.byName = append(list.byName, re)
+		}
+	}
+	return list, nil
+}
+
+func (list globList) empty() bool {
+	return len(list.byName) == 0 && len(list.byPath) == 0
+}
+
+// match - rel is slash separated
+func (list globList) match(rel string) bool {
+	name := path.Base(rel)
+	for _, re := range list.byName {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	for _, re := range list.byPath {
+		if re.MatchString(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// fileFilter - decides which files are examined, whether or not -r is used
+type fileFilter struct {
+	include globList
+	exclude globList
+}
+
+func newFileFilter(opts Options) (fileFilter, error) {
+	var filter fileFilter
+	var err error
+	if filter.include, err = newGlobList(opts.Include); err != nil {
+		return filter, err
+	}
+	filter.exclude, err = newGlobList(opts.Exclude)
+	return filter, err
+}
+
+// wantFile - rel is the slash separated path relative to the directory being walked, or the
+// name given on the command line
+func (filter fileFilter) wantFile(rel string) bool {
+	if filter.exclude.match(rel) {
+		return false
+	}
+	return filter.include.empty() || filter.include.match(rel)
+}
+
+// wantDir - an excluded directory is not descended into
+func (filter fileFilter) wantDir(rel string) bool {
+	return !filter.exclude.match(rel)
+}
+
+// ValidateGlobs - return an error for the first glob which can not be used with --include or --exclude
+func ValidateGlobs(globs []string) error {
+	_, err := newGlobList(globs)
+	return err
+}
+
+// isHidden - files and directories starting with a dot are hidden on every OS
+func isHidden(name string) bool {
+	return len(name) > 1 && strings.HasPrefix(name, ".") && name != ".."
+}
+
+// walker - visited holds the real path of each directory so that symbolic link loops are skipped
+type walker struct {
+	opts    Options
+	filter  fileFilter
+	ignore  *ignoreMatcher // nil with --no-ignore
+	visited map[string]bool
+	files   []string
+}
+
+// walkDirectory - return all files below root which should be examined
+func walkDirectory(root string, opts Options, filter fileFilter) []string {
+	w := walker{opts: opts, filter: filter, visited: make(map[string]bool)}
+	if !opts.NoIgnore {
+		w.ignore = newIgnoreMatcher(root)
+	}
+	w.walk(root, root, 0)
+	return w.files
+}
+
+// walk - dir is root or a directory below it, which is depth levels deep
+func (w *walker) walk(root, dir string, depth int) {
+	if w.opts.FollowSymlinks {
+		real, err := filepath.EvalSymlinks(dir)
+		if err == nil {
+			if w.visited[real] {
+				_, _ = fmt.Fprintf(os.Stderr, "skipping directory already visited, possible symlink loop: %s\n", dir)
+				return
+			}
+			w.visited[real] = true
+		}
+	}
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "error: %s\n", err)
+			if d != nil && d.IsDir() && p != dir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if p == dir {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(root, p)
+		rel = filepath.ToSlash(rel)
+		below, _ := filepath.Rel(dir, p)
+		level := depth + strings.Count(filepath.ToSlash(below), "/") + 1
+		skipDir := filepath.SkipDir
+		if !d.IsDir() {
+			skipDir = nil
+		}
+		if !w.opts.Hidden && isHidden(d.Name()) || w.ignore != nil && d.IsDir() && d.Name() == ".git" {
+			return skipDir
+		}
+
+		if d.Type()&fs.ModeSymlink != 0 {
+			if !w.opts.FollowSymlinks {
+				return nil
+			}
+			info, err := os.Stat(p)
+			if err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "error: %s\n", err)
+				return nil
+			}
+			if w.ignore != nil && w.ignore.ignored(p, info.IsDir()) {
+				return nil
+			}
+			if info.IsDir() {
+				if w.withinDepth(level+1) && w.filter.wantDir(rel) {
+					w.walk(root, p, level)
+				}
+				return nil
+			}
+		} else if w.ignore != nil && w.ignore.ignored(p, d.IsDir()) {
+			return skipDir
+		}
+
+		if d.IsDir() {
+			if !w.filter.wantDir(rel) || !w.withinDepth(level+1) {
+				return filepath.SkipDir
+			}
+			if w.opts.FollowSymlinks {
+				if real, err := filepath.EvalSymlinks(p); err == nil {
+					if w.visited[real] {
+						return filepath.SkipDir
+					}
+					w.visited[real] = true
+				}
+			}
+			return nil
+		}
+
+		if w.filter.wantFile(rel) {
+			w.files = append(w.files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "error: %s\n", err)
+	}
+}
+
+// withinDepth - files directly inside the walked directory are at depth 1
+func (w *walker) withinDepth(depth int) bool {
+	return w.opts.MaxDepth <= 0 || depth <= w.opts.MaxDepth
+}
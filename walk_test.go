@@ -0,0 +1,69 @@
+package chars
+
+import "testing"
+
+func TestGlobToRegexp(t *testing.T) {
+	tests := []struct {
+		glob, name string
+		match      bool
+	}{
+		{"*.go", "chars.go", true},
+		{"*.go", "chars.go.orig", false},
+		{"*.go", "cmd/cmd.go", false},
+		{"?.txt", "a.txt", true},
+		{"?.txt", "ab.txt", false},
+		{"?.txt", "/.txt", false},
+		{"[abc].txt", "b.txt", true},
+		{"[!abc].txt", "b.txt", false},
+		{"[!abc].txt", "d.txt", true},
+		{"[a-c]*", "cat", true},
+		{"**/testdata", "testdata", true},
+		{"**/testdata", "a/b/testdata", true},
+		{"**/testdata", "a/btestdata", false},
+		{"vendor/**", "vendor/a/b.go", true},
+		{"a/**/b", "a/x/y/b", true},
+		{"a/**/b", "a/b", true},
+		{`\*.go`, "*.go", true},
+		{`\*.go`, "x.go", false},
+		{"a.b", "axb", false},
+		{"a+b(1)", "a+b(1)", true},
+	}
+	for _, tt := range tests {
+		re, err := globToRegexp(tt.glob)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.glob, err)
+		}
+		if got := re.MatchString(tt.name); got != tt.match {
+			t.Errorf("%s matching %s = %v, want %v", tt.glob, tt.name, got, tt.match)
+		}
+	}
+
+	if _, err := globToRegexp("[abc.txt"); err == nil {
+		t.Errorf("an unterminated character class was accepted")
+	}
+}
+
+// TestGlobListAnchoring - globs containing a / match the relative path, all others only the file name
+func TestGlobListAnchoring(t *testing.T) {
+	list, err := newGlobList([]string{"*.md", "docs/*.txt", "build/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		rel   string
+		match bool
+	}{
+		{"README.md", true},
+		{"a/b/notes.md", true},
+		{"docs/a.txt", true},
+		{"src/docs/a.txt", false},
+		{"a.txt", false},
+		{"build", true},
+		{"src/build", true},
+	}
+	for _, tt := range tests {
+		if got := list.match(tt.rel); got != tt.match {
+			t.Errorf("match %s = %v, want %v", tt.rel, got, tt.match)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package chars
+
+/*
+whitespace.go
+
+Fix mode stages for tabs and trailing whitespace: --expand-tabs, --unexpand-tabs and
+--strip-trailing-ws. Columns count characters, the same as --locations, and start over
+after each LF or CR.
+*/
+
+import (
+	"bytes"
+
+	"golang.org/x/text/transform"
+)
+
+// MaxTabSize - the largest value accepted by --expand-tabs and --unexpand-tabs
+const MaxTabSize int = 64
+
+// isLineEnd - LF, or the CR of either a CRLF or a lone CR
+func isLineEnd(b byte) bool {
+	return b == '\n' || b == '\r'
+}
+
+// tabExpander - replace every tab with spaces up to the next tab stop
+type tabExpander struct {
+	size  int
+	col   int
+	count uint64
+}
+
+func (te *tabExpander) name() string  { return "expand tabs" }
+func (te *tabExpander) edits() uint64 { return te.count }
+func (te *tabExpander) Reset()        { te.col, te.count = 0, 0 }
+
+func (te *tabExpander) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for ; nSrc < len(src); nSrc++ {
+		b := src[nSrc]
+		if b == '\t' {
+			width := te.size - te.col%te.size
+			if len(dst)-nDst < width {
+				return nDst, nSrc, transform.ErrShortDst
+			}
+			nDst += copy(dst[nDst:], bytes.Repeat([]byte{' '}, width))
+			te.col += width
+			te.count++
+			continue
+		}
+
+		if nDst == len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		dst[nDst] = b
+		nDst++
+		if isLineEnd(b) {
+			te.col = 0
+		} else if b < 0x80 || b >= 0xc0 {
+			te.col++ // continuation bytes of a UTF-8 character do not start a new column
+		}
+	}
+	return nDst, nSrc, nil
+}
+
+// tabUnexpander - rewrite the indentation of each line as tabs followed by fewer than size spaces;
+// whitespace after the first other character is left alone
+type tabUnexpander struct {
+	size    int
+	started bool   // the indentation of the current line has been written
+	col     int    // width of the indentation read so far
+	raw     []byte // the indentation as it was read
+	count   uint64
+}
+
+func (tu *tabUnexpander) name() string  { return "unexpand tabs" }
+func (tu *tabUnexpander) edits() uint64 { return tu.count }
+
+func (tu *tabUnexpander) Reset() {
+	tu.started, tu.col, tu.raw, tu.count = false, 0, tu.raw[:0], 0
+}
+
+// indent - the indentation of the current line using as many tabs as possible
+func (tu *tabUnexpander) indent() []byte {
+	tabs := bytes.Repeat([]byte{'\t'}, tu.col/tu.size)
+	return append(tabs, bytes.Repeat([]byte{' '}, tu.col%tu.size)...)
+}
+
+// flush - write the indentation of the current line followed by extra bytes, if they fit in dst
+func (tu *tabUnexpander) flush(dst []byte, extra int) (int, bool) {
+	indent := tu.indent()
+	if len(dst) < len(indent)+extra {
+		return 0, false
+	}
+	if !bytes.Equal(indent, tu.raw) {
+		tu.count++
+	}
+	tu.started, tu.col, tu.raw = true, 0, tu.raw[:0]
+	return copy(dst, indent), true
+}
+
+func (tu *tabUnexpander) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for ; nSrc < len(src); nSrc++ {
+		b := src[nSrc]
+		if !tu.started && (b == ' ' || b == '\t') {
+			if b == '\t' {
+				tu.col += tu.size - tu.col%tu.size
+			} else {
+				tu.col++
+			}
+			tu.raw = append(tu.raw, b)
+			continue
+		}
+
+		if !tu.started {
+			n, ok := tu.flush(dst[nDst:], 1)
+			if !ok {
+				return nDst, nSrc, transform.ErrShortDst
+			}
+			nDst += n
+		}
+		if nDst == len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		dst[nDst] = b
+		nDst++
+		if isLineEnd(b) {
+			tu.started = false
+		}
+	}
+	if atEOF && len(tu.raw) > 0 {
+		n, ok := tu.flush(dst[nDst:], 0)
+		if !ok {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		nDst += n
+	}
+	return nDst, nSrc, nil
+}
+
+// trailingStripper - remove spaces and tabs at the end of each line; whitespace is held back
+// until it is known whether anything other than a line ending follows it
+type trailingStripper struct {
+	pending []byte
+	count   uint64
+}
+
+func (ts *trailingStripper) name() string  { return "strip trailing whitespace" }
+func (ts *trailingStripper) edits() uint64 { return ts.count }
+func (ts *trailingStripper) Reset()        { ts.pending, ts.count = ts.pending[:0], 0 }
+
+func (ts *trailingStripper) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for ; nSrc < len(src); nSrc++ {
+		b := src[nSrc]
+		switch {
+		case b == ' ' || b == '\t':
+			ts.pending = append(ts.pending, b)
+			continue
+		case isLineEnd(b):
+			if len(ts.pending) > 0 {
+				ts.pending = ts.pending[:0]
+				ts.count++
+			}
+		case len(ts.pending) > 0:
+			// whitespace within a line is kept, even when there is more of it than fits in dst
+			n := copy(dst[nDst:], ts.pending)
+			nDst += n
+			ts.pending = ts.pending[:copy(ts.pending, ts.pending[n:])]
+			if len(ts.pending) > 0 {
+				return nDst, nSrc, transform.ErrShortDst
+			}
+		}
+
+		if nDst == len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		dst[nDst] = b
+		nDst++
+	}
+	if atEOF && len(ts.pending) > 0 {
+		ts.pending = ts.pending[:0]
+		ts.count++
+	}
+	return nDst, nSrc, nil
+}
@@ -0,0 +1,58 @@
+package chars
+
+import "testing"
+
+func TestTabExpander(t *testing.T) {
+	tests := []struct {
+		size        int
+		input, want string
+		edits       uint64
+	}{
+		{4, "\ta\n", "    a\n", 1},
+		{4, "ab\tc\td\n", "ab  c   d\n", 2},
+		{4, "é\tx\n", "é   x\n", 1},
+		{8, "\t\r\n\tb", "        \r\n        b", 2},
+		{4, "abc\n", "abc\n", 0},
+	}
+	for _, tt := range tests {
+		checkStage(t, &tabExpander{size: tt.size}, tt.input, tt.want, tt.edits)
+	}
+}
+
+func TestTabUnexpander(t *testing.T) {
+	tests := []struct {
+		size        int
+		input, want string
+		edits       uint64
+	}{
+		{4, "        a\n", "\t\ta\n", 1},
+		{4, "      a  b\n", "\t  a  b\n", 1},
+		{4, "  \t  a\n", "\t  a\n", 1},
+		{4, "\ta\n    b\n", "\ta\n\tb\n", 1},
+		// indentation at EOF, and on a line with nothing else, is rewritten too
+		{4, "a\n        ", "a\n\t\t", 1},
+		{4, "    \r\n", "\t\r\n", 1},
+		{4, "\t\ta\n", "\t\ta\n", 0},
+	}
+	for _, tt := range tests {
+		checkStage(t, &tabUnexpander{size: tt.size}, tt.input, tt.want, tt.edits)
+	}
+}
+
+func TestTrailingStripper(t *testing.T) {
+	tests := []struct {
+		input, want string
+		edits       uint64
+	}{
+		{"a  \nb\t\n", "a\nb\n", 2},
+		{"a \t b\n", "a \t b\n", 0},
+		{"a   \r\nb  ", "a\r\nb", 2},
+		// whitespace within a line is kept even when there is more of it than fits in one block
+		{"a                    b   \n", "a                    b\n", 1},
+		{"   \n\n", "\n\n", 1},
+		{"", "", 0},
+	}
+	for _, tt := range tests {
+		checkStage(t, &trailingStripper{}, tt.input, tt.want, tt.edits)
+	}
+}
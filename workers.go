@@ -0,0 +1,95 @@
+package chars
+
+/*
+workers.go
+
+Examine files concurrently with a bounded pool of workers; results are kept in the same
+order as the list of files so that output does not depend on which worker finishes first
+*/
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+)
+
+// scanResult - each worker only writes to the slots of the files it was given, so no lock is needed
+type scanResult struct {
+	stats SpecialChars
+	ok    bool
+}
+
+// scanFiles - examine each file using opts.Workers goroutines (runtime.NumCPU() when not set) and
+// return the results in the order of filenames; skipped files are omitted
+func scanFiles(filenames []string, opts Options) []SpecialChars {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(filenames) {
+		workers = len(filenames)
+	}
+
+	results := make([]scanResult, len(filenames))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i].stats, results[i].ok = scanFile(filenames[i], opts)
+			}
+		}()
+	}
+	for i := range filenames {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	allStats := make([]SpecialChars, 0, len(filenames))
+	for _, result := range results {
+		if result.ok {
+			allStats = append(allStats, result.stats)
+		}
+	}
+	return allStats
+}
+
+// scanFile - open and examine a single file; returns false when the file was skipped
+func scanFile(filename string, opts Options) (SpecialChars, bool) {
+	// fmt.Println("checking file:", filename)
+	file, err := os.Open(filename)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "%s\n", err)
+		return SpecialChars{}, false
+	}
+
+	reader := bufio.NewReader(file)
+	stats, charsErr := searchForSpecialChars(filename, reader, opts)
+	_ = file.Close()
+	if charsErr.code == 1 {
+		// output error message except for an unwanted binary file
+		_, _ = fmt.Fprintf(os.Stderr, "error #%d: %s\n", charsErr.code, charsErr.err)
+		return SpecialChars{}, false
+	} else if charsErr.code != 0 {
+		return SpecialChars{}, false
+	}
+	if opts.Suggest {
+		hunks, err := suggestFile(filename, stats)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		} else if len(hunks) > 0 {
+			stats.hunks = hunks
+			stats.Suggestion = renderDiff(filename, hunks)
+		}
+	}
+	// a fix is made after the suggestion so that the suggestion is for the file as it was found
+	if opts.Fixing() {
+		stats.Fix = fixFile(filename, stats, opts)
+	}
+	return stats, true
+}
@@ -0,0 +1,44 @@
+package chars
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// benchmarkFiles - write count files of about size bytes each, with a mix of line endings, tabs and non-ASCII text
+func benchmarkFiles(b *testing.B, count, size int) []string {
+	b.Helper()
+	dir := b.TempDir()
+	line := "\tfunc main() {\r\n\t\tfmt.Println(\"naïve café\")   \n}\n"
+	text := strings.Repeat(line, size/len(line)+1)
+	filenames := make([]string, count)
+	for i := range filenames {
+		filenames[i] = filepath.Join(dir, fmt.Sprintf("file%03d.txt", i))
+		if err := os.WriteFile(filenames[i], []byte(text), 0o644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return filenames
+}
+
+// BenchmarkScanFiles - compare examining files one at a time with -p 1 against one worker per CPU,
+// which is only run once on a machine with a single CPU
+func BenchmarkScanFiles(b *testing.B) {
+	filenames := benchmarkFiles(b, 64, 1<<20)
+	for _, workers := range slices.Compact([]int{1, runtime.NumCPU()}) {
+		b.Run(fmt.Sprintf("p=%d", workers), func(b *testing.B) {
+			opts := Options{Workers: workers}
+			b.SetBytes(int64(len(filenames)) << 20)
+			for i := 0; i < b.N; i++ {
+				if stats := scanFiles(filenames, opts); len(stats) != len(filenames) {
+					b.Fatalf("examined %d files, want %d", len(stats), len(filenames))
+				}
+			}
+		})
+	}
+}